@@ -1,6 +1,7 @@
 package refloat_test
 
 import (
+	"errors"
 	"math"
 	"math/rand"
 	"strconv"
@@ -177,3 +178,44 @@ func BenchmarkParseFloat32Special(b *testing.B) {
 		})
 	})
 }
+
+func benchmarkParseBoth(b *testing.B, fnc func(string) (float32, float64, error), tab []float64String) {
+	for try := 0; try < b.N; try++ {
+		ent := tab[try%len(tab)]
+		_, f64, err := fnc(ent.inp)
+		// float32 overflows for some of them.
+		if err != nil && !errors.Is(err, refloat.ErrRange) {
+			b.Fatal(ent.inp, err)
+		}
+		if f64 == f64 && ent.out == ent.out && f64 != ent.out {
+			b.Fatal(ent.inp, f64)
+		}
+	}
+}
+
+// parseTwice is ParseBoth as two separate calls.
+func parseTwice(num string) (float32, float64, error) {
+	f32, err32 := refloat.ParseFloat32(num)
+	f64, err := refloat.ParseFloat64(num)
+	if err == nil {
+		err = err32
+	}
+	return f32, f64, err
+}
+
+func BenchmarkParseBoth(b *testing.B) {
+	once.Do(initOnce)
+	b.ResetTimer()
+	b.Run("twice/bits", func(b *testing.B) {
+		benchmarkParseBoth(b, parseTwice, randbits64)
+	})
+	b.Run("twice/norm", func(b *testing.B) {
+		benchmarkParseBoth(b, parseTwice, randnorm64)
+	})
+	b.Run("both/bits", func(b *testing.B) {
+		benchmarkParseBoth(b, refloat.ParseBoth, randbits64)
+	})
+	b.Run("both/norm", func(b *testing.B) {
+		benchmarkParseBoth(b, refloat.ParseBoth, randnorm64)
+	})
+}
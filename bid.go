@@ -0,0 +1,318 @@
+package refloat
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// A Decimal128 is the bits of an IEEE-754 decimal128 number in the binary
+// integer decimal (BID) encoding, which BSON uses too. Hi has the sign,
+// the combination field and the upper 49 bits of the coefficient.
+type Decimal128 struct {
+	Hi, Lo uint64
+}
+
+// a bid is a decimal floating-point format of IEEE-754 in the binary
+// integer decimal encoding, where the coefficient is an integer.
+type bid struct {
+	width int       // the number of bits in total.
+	digs  int       // the number of digits of the coefficient.
+	emax  int       // the largest exponent, of the form d.ddd.
+	ebits int       // the number of bits of the exponent.
+	pow   [2]uint64 // 10^digs, as hi:lo.
+}
+
+var (
+	decimal64  = bid{width: 64, digs: 16, emax: 384, ebits: 10, pow: [2]uint64{0, 0x2386f26fc10000}}
+	decimal128 = bid{width: 128, digs: 34, emax: 6144, ebits: 14, pow: [2]uint64{0x1ed09bead87c0, 0x378d8e6400000000}}
+)
+
+// ParseDecimal64 converts num to an IEEE-754 decimal64 number in the BID
+// encoding and returns its bits. num has the same syntax as for ParseFloat
+// without hexadecimals.
+//
+// The coefficient and the exponent are the ones written, so "1.20" and
+// "1.2" are different members of the same cohort. Coefficients with more
+// than 16 digits are rounded in the rounding mode of the Parser, and so are
+// the ones of subnormals. Exponents that are too large are clamped by adding
+// zeros to the coefficient when they fit; if not, it's ErrRange.
+//
+// The errors are the same as ParseFloat, for the range of decimal64.
+func ParseDecimal64(num string) (uint64, error) {
+	return std.ParseDecimal64(num)
+}
+
+// ParseDecimal128 is like ParseDecimal64, but for decimal128,
+// which has 34 digits.
+func ParseDecimal128(num string) (Decimal128, error) {
+	return std.ParseDecimal128(num)
+}
+
+// ParseDecimal64 is like the package-level ParseDecimal64, but with the syntax of p.
+// With p.NaNPayloads, the payload is the coefficient of the NaN, which
+// has to be less than 10^15.
+func (p *Parser) ParseDecimal64(num string) (uint64, error) {
+	const fnc = "ParseDecimal64"
+	_, lo, err := parseBID(num, p, decimal64, fnc)
+	return lo, err
+}
+
+// ParseDecimal128 is like the package-level ParseDecimal128, but with the syntax of p.
+// With p.NaNPayloads, the payload is the coefficient of the NaN, which
+// has to be less than 10^33.
+func (p *Parser) ParseDecimal128(num string) (Decimal128, error) {
+	const fnc = "ParseDecimal128"
+	hi, lo, err := parseBID(num, p, decimal128, fnc)
+	return Decimal128{Hi: hi, Lo: lo}, err
+}
+
+// Decimal64ToFloat returns the float64 nearest to the decimal64 bits,
+// rounded to nearest even. Like conversions in Go, values that are too
+// large become ±Inf.
+func Decimal64ToFloat(bits uint64) float64 {
+	return bidToFloat(0, bits, decimal64)
+}
+
+// Decimal128ToFloat is like Decimal64ToFloat, but for decimal128.
+func Decimal128ToFloat(d Decimal128) float64 {
+	return bidToFloat(d.Hi, d.Lo, decimal128)
+}
+
+// parseBID parses num to d, and returns its bits as hi:lo.
+func parseBID[T text](num T, p *Parser, d bid, fnc string) (uint64, uint64, error) {
+	bias := d.emax + d.digs - 2
+	// the exponent of the first digit beyond bias + 2*d.digs + 20 gives
+	// the same result, including the cohort. it's 20 digits from exp10.
+	limit := bias + 2*d.digs + 40
+	// decimal formats can't represent hexadecimals exactly.
+	chk := *p
+	chk.NoHex = true
+	dec, _, f64, read, err := scan64(num, &chk, limit)
+	f64, err = whole(num, f64, read, err, fnc)
+	if err != nil && err.(*NumError).Err == ErrSyntax {
+		return 0, 0, err
+	}
+
+	var sign, offset int
+	if num[offset] == '+' {
+		offset++
+	} else if num[offset] == '-' {
+		offset++
+		sign = 1
+	}
+	// the top 5 bits of the combination field: 11110 for Infs,
+	// and 11111 for NaNs, followed by 1 for signaling ones.
+	shi, slo := lsh128(0, uint64(sign), d.width-1)
+	if math.IsInf(f64, 0) {
+		hi, lo := lsh128(0, 0x78, d.width-8)
+		return hi | shi, lo | slo, nil
+	}
+	if f64 != f64 {
+		hi, lo := lsh128(0, 0x7c, d.width-8)
+		if !p.NaNPayloads {
+			return hi | shi, lo | slo, nil
+		}
+		if len(p.Specials) != 0 {
+			if _, read := special(num[offset:], p); read != 0 {
+				return hi | shi, lo | slo, nil
+			}
+		}
+		// already validated, see above.
+		signal, load, over, _, _ := nanRead(num, offset, p)
+		if signal {
+			hi, lo = lsh128(0, 0x7e, d.width-8)
+		}
+		// the payload has one digit less than the coefficient.
+		phi, plo, _ := div10(d.pow[0], d.pow[1])
+		if over || phi == 0 && load >= plo {
+			return hi | shi, lo | slo, errorRange(fnc, num)
+		}
+		return hi | shi, lo | slo | load, nil
+	}
+
+	// the significant digits start at the first non-zero digit.
+	digs := dec.drop
+	for mant := dec.mant; mant != 0; mant /= 10 {
+		digs++
+	}
+	// the exponent of the last digit.
+	exp := dec.exp10 - dec.drop
+
+	// the digits to cut off, for the coefficient and for subnormals.
+	drop := max(digs-d.digs, -bias-exp, 0)
+	exp += drop
+	var hi, lo uint64
+	var half uint64
+	var trunc bool
+	if drop > digs {
+		// even the first digit is below the half.
+		trunc = true
+	}
+	mark, sep, _ := p.notation()
+	var point bool
+	var idx int
+	for offset = dec.begin; offset < dec.end; offset++ {
+		char := num[offset]
+		if char == mark && !point {
+			point = true
+			continue
+		}
+		if char == sep {
+			continue
+		}
+		char -= '0'
+		if idx == 0 && char == 0 {
+			continue
+		}
+		switch {
+		case idx < digs-drop:
+			hi, lo = mul10(hi, lo, uint64(char))
+		case idx == digs-drop:
+			half = uint64(char)
+		default:
+			trunc = trunc || char != 0
+		}
+		idx++
+	}
+
+	// round as binary, where the half bit and the bits below
+	// are made from the digit below the coefficient.
+	inexact := trunc || half != 0
+	inc := round(lo&1<<1|half/5, trunc || half%5 != 0, sign, p.Rounding)
+	var carry uint64
+	lo, carry = bits.Add64(lo, inc, 0)
+	hi += carry
+	if hi == d.pow[0] && lo == d.pow[1] {
+		// 999 became 1000, which has one digit too many.
+		hi, lo, _ = div10(hi, lo)
+		exp++
+	}
+
+	tiny := exp+digs128(hi, lo)-1 < 1-d.emax
+	zero := hi|lo == 0
+	for exp > d.emax-d.digs+1 && !zero {
+		// clamped: add zeros to the coefficient, if it has room for them.
+		nhi, nlo := mul10(hi, lo, 0)
+		if nhi > d.pow[0] || nhi == d.pow[0] && nlo >= d.pow[1] {
+			break
+		}
+		hi, lo = nhi, nlo
+		exp--
+	}
+	if zero {
+		exp = min(max(exp, -bias), d.emax-d.digs+1)
+	}
+	if exp > d.emax-d.digs+1 {
+		if p.toInf(sign) {
+			hi, lo := lsh128(0, 0x78, d.width-8)
+			return hi | shi, lo | slo, errorOf(fnc, num, p.check(true, p.overflow()))
+		}
+		// the largest finite number is 999...9 with the largest exponent.
+		hi, lo = d.pow[0], d.pow[1]-1
+		if d.pow[1] == 0 {
+			hi--
+		}
+		exp = d.emax - d.digs + 1
+		ehi, elo := d.pack(hi, lo, exp)
+		return ehi | shi, elo | slo, errorOf(fnc, num, p.check(true, p.overflow()))
+	}
+	if tiny && digs != 0 && p.FlushToZero {
+		hi, lo, exp, inexact = 0, 0, -bias, true
+	}
+	hi, lo = d.pack(hi, lo, exp)
+	hi, lo = hi|shi, lo|slo
+	if tiny && digs != 0 && p.Underflow {
+		return hi, lo, errorOf(fnc, num, ErrUnderflow)
+	}
+	if inexact && p.Exact {
+		return hi, lo, errorOf(fnc, num, ErrInexact)
+	}
+	return hi, lo, nil
+}
+
+// pack returns the bits of the coefficient hi:lo and the exponent of its
+// last digit, without the sign.
+func (d bid) pack(hi, lo uint64, exp int) (uint64, uint64) {
+	shift := d.width - 1 - d.ebits
+	biased := uint64(exp + d.emax + d.digs - 2)
+	if len128(hi, lo) <= shift {
+		ehi, elo := lsh128(0, biased, shift)
+		return hi | ehi, lo | elo
+	}
+	// coefficients which don't fit below the exponent begin with 100,
+	// which is implied by 11 at the top, and the exponent comes after it.
+	ehi, elo := lsh128(0, 3<<d.ebits|biased, shift-2)
+	mhi, mlo := lsh128(0, 1, shift-2)
+	mlo, borrow := bits.Sub64(mlo, 1, 0)
+	return hi&(mhi-borrow) | ehi, lo&mlo | elo
+}
+
+// bidToFloat converts the bits hi:lo of d to the nearest float64.
+func bidToFloat(hi, lo uint64, d bid) float64 {
+	shift := d.width - 1 - d.ebits
+	_, top, _ := rsh128(hi, lo, d.width-8)
+	sign := top >> 7
+	switch {
+	case top&0x7c == 0x7c:
+		return math.NaN()
+	case top&0x7c == 0x78:
+		return math.Inf(1 - 2*int(sign))
+	}
+	var exp uint64
+	_, comb, _ := rsh128(hi, lo, d.width-3)
+	if comb&3 == 3 {
+		// see pack. the coefficient begins with the implied 100.
+		_, exp, _ = rsh128(hi, lo, shift-2)
+		mhi, mlo := lsh128(0, 1, shift-2)
+		mlo, borrow := bits.Sub64(mlo, 1, 0)
+		ihi, ilo := lsh128(0, 1, shift)
+		hi, lo = hi&(mhi-borrow)|ihi, lo&mlo|ilo
+	} else {
+		_, exp, _ = rsh128(hi, lo, shift)
+		mhi, mlo := lsh128(0, 1, shift)
+		mlo, borrow := bits.Sub64(mlo, 1, 0)
+		hi, lo = hi&(mhi-borrow), lo&mlo
+	}
+	exp &= 1<<d.ebits - 1
+	if hi > d.pow[0] || hi == d.pow[0] && lo >= d.pow[1] {
+		// coefficients of 10^digs or more are not canonical, and are
+		// read as zeros. that's all of the above in decimal128.
+		hi, lo = 0, 0
+	}
+
+	exp10 := int(exp) - (d.emax + d.digs - 2)
+	if hi == 0 {
+		f64, _ := fromDecimal(lo, nil, exp10, sign != 0, 64, &std)
+		return f64
+	}
+	wide := new(big.Int).SetUint64(hi)
+	wide.Lsh(wide, 64)
+	wide.Add(wide, new(big.Int).SetUint64(lo))
+	f64, _ := fromDecimal(0, wide, exp10, sign != 0, 64, &std)
+	return f64
+}
+
+// mul10 returns hi:lo*10+dig.
+func mul10(hi, lo, dig uint64) (uint64, uint64) {
+	carry, lo := bits.Mul64(lo, 10)
+	lo, add := bits.Add64(lo, dig, 0)
+	return hi*10 + carry + add, lo
+}
+
+// div10 returns hi:lo/10 and the remainder.
+func div10(hi, lo uint64) (uint64, uint64, uint64) {
+	quo, rem := bits.Div64(0, hi, 10)
+	low, rem := bits.Div64(rem, lo, 10)
+	return quo, low, rem
+}
+
+// digs128 returns the number of digits of hi:lo, which is 0 for 0.
+func digs128(hi, lo uint64) int {
+	var digs int
+	for hi|lo != 0 {
+		hi, lo, _ = div10(hi, lo)
+		digs++
+	}
+	return digs
+}
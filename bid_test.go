@@ -0,0 +1,169 @@
+package refloat_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+var decimal64tests = []struct {
+	inp string
+	out uint64
+	err error
+}{
+	{"1", 0x31c0000000000001, nil},
+	{"-1", 0xb1c0000000000001, nil},
+	// the cohort is kept.
+	{"1.0", 0x31a000000000000a, nil},
+	{"1.20", 0x3180000000000078, nil},
+	{"0.1", 0x31a0000000000001, nil},
+	{"-0", 0xb1c0000000000000, nil},
+	{"0e-1000", 0x0000000000000000, nil},
+	{"0e1000", 0x5fe0000000000000, nil},
+	// coefficients of 2^53 or more have the other form.
+	{"9999999999999999", 0x6c7386f26fc0ffff, nil},
+	{"9.999999999999999e384", 0x77fb86f26fc0ffff, nil},
+	// rounded to 16 digits.
+	{"12345678901234567", 0x31e462d53c8abac1, nil},
+	{"12345678901234565", 0x31e462d53c8abac0, nil},
+	{"12345678901234565000000000000000000000000000001", 0x35a462d53c8abac1, nil},
+	{"99999999999999995", 0x32038d7ea4c68000, nil},
+	// clamped.
+	{"1e384", 0x5fe38d7ea4c68000, nil},
+	{"1e385", 0x7800000000000000, ErrRange},
+	{"-1e1000", 0xf800000000000000, ErrRange},
+	// subnormals.
+	{"1e-398", 0x0000000000000001, nil},
+	{"1.5e-398", 0x0000000000000002, nil},
+	{"5e-399", 0x0000000000000000, nil},
+	{"6e-399", 0x0000000000000001, nil},
+	{"1.2345e-395", 0x00000000000004d2, nil},
+	{"inf", 0x7800000000000000, nil},
+	{"-Infinity", 0xf800000000000000, nil},
+	{"nan", 0x7c00000000000000, nil},
+	{"1_000", 0x31c00000000003e8, nil},
+	{"0x1p0", 0, ErrSyntax},
+	{"1e", 0, ErrSyntax},
+}
+
+var decimal128tests = []struct {
+	inp string
+	out Decimal128
+	err error
+}{
+	{"1", Decimal128{0x3040000000000000, 1}, nil},
+	{"-1.0", Decimal128{0xb03e000000000000, 10}, nil},
+	{"0.1", Decimal128{0x303e000000000000, 1}, nil},
+	{"9999999999999999999999999999999999", Decimal128{0x3041ed09bead87c0, 0x378d8e63ffffffff}, nil},
+	{"123456789012345678901234567890123456", Decimal128{0x30443cde6fff9732, 0xde825cd07e96aff3}, nil},
+	{"9.999999999999999999999999999999999e6144", Decimal128{0x5fffed09bead87c0, 0x378d8e63ffffffff}, nil},
+	{"1e6144", Decimal128{0x5ffe314dc6448d93, 0x38c15b0a00000000}, nil},
+	{"1e6145", Decimal128{0x7800000000000000, 0}, ErrRange},
+	{"1e-6176", Decimal128{0, 1}, nil},
+	{"1e-6177", Decimal128{0, 0}, nil},
+	{"0e-7000", Decimal128{0, 0}, nil},
+	{"-inf", Decimal128{0xf800000000000000, 0}, nil},
+	{"nan", Decimal128{0x7c00000000000000, 0}, nil},
+}
+
+func TestParseDecimal(t *testing.T) {
+	for _, test := range decimal64tests {
+		out, err := ParseDecimal64(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("ParseDecimal64(%q) = %#016x, %v want %#016x, %v", test.inp, out, err, test.out, test.err)
+		}
+	}
+	for _, test := range decimal128tests {
+		out, err := ParseDecimal128(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("ParseDecimal128(%q) = %#x, %v want %#x, %v", test.inp, out, err, test.out, test.err)
+		}
+	}
+	_, err := ParseDecimal64("1.5e")
+	var num *NumError
+	if !errors.As(err, &num) || num.Func != "ParseDecimal64" || !errors.Is(err, ErrExponent) {
+		t.Errorf("ParseDecimal64(%q) = _, %#v; want a *NumError for ParseDecimal64", "1.5e", err)
+	}
+}
+
+func TestParseDecimalParser(t *testing.T) {
+	for _, test := range []struct {
+		par Parser
+		inp string
+		out uint64
+		err error
+	}{
+		{Parser{Exact: true}, "1.20", 0x3180000000000078, nil},
+		{Parser{Exact: true}, "12345678901234567", 0x31e462d53c8abac1, ErrInexact},
+		{Parser{Rounding: big.ToZero}, "12345678901234567", 0x31e462d53c8abac0, nil},
+		{Parser{Rounding: big.ToNegativeInf}, "-12345678901234561", 0xb1e462d53c8abac1, nil},
+		{Parser{Rounding: big.ToPositiveInf}, "1e-500", 0x0000000000000001, nil},
+		{Parser{Overflow: OverflowSaturate}, "1e385", 0x77fb86f26fc0ffff, ErrRange},
+		{Parser{Overflow: OverflowSaturateSilent}, "-1e385", 0xf7fb86f26fc0ffff, nil},
+		{Parser{Underflow: true}, "1e-390", 0x0100000000000001, ErrUnderflow},
+		{Parser{Underflow: true}, "1e-383", 0x01e0000000000001, nil},
+		{Parser{FlushToZero: true}, "-1e-390", 0x8000000000000000, nil},
+		{Parser{NaNPayloads: true}, "-nan(123)", 0xfc0000000000007b, nil},
+		{Parser{NaNPayloads: true}, "snan", 0x7e00000000000000, nil},
+		{Parser{NaNPayloads: true}, "nan(1000000000000000)", 0x7c00000000000000, ErrRange},
+		{Parser{Locale: Locale{Point: ',', Group: '.'}}, "1.000,50", 0x31800000000186d2, nil},
+	} {
+		out, err := test.par.ParseDecimal64(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("%+v.ParseDecimal64(%q) = %#016x, %v want %#016x, %v", test.par, test.inp, out, err, test.out, test.err)
+		}
+	}
+}
+
+func TestDecimalToFloat(t *testing.T) {
+	for _, test := range []struct {
+		inp uint64
+		out float64
+	}{
+		{0x31c0000000000001, 1},
+		{0x31a0000000000001, 0.1},
+		{0xb180000000000078, -1.2},
+		{0x6c7386f26fc0ffff, 9999999999999999},
+		{0x77fb86f26fc0ffff, math.Inf(1)},
+		{0x0000000000000001, 0},
+		{0x7800000000000000, math.Inf(1)},
+		// not canonical.
+		{0x6ff386f26fc10000, 0},
+	} {
+		if out := Decimal64ToFloat(test.inp); out != test.out || math.Signbit(out) != math.Signbit(test.out) {
+			t.Errorf("Decimal64ToFloat(%#016x) = %v want %v", test.inp, out, test.out)
+		}
+	}
+	if out := Decimal64ToFloat(0x7c00000000000000); out == out {
+		t.Errorf("Decimal64ToFloat(%#016x) = %v want NaN", uint64(0x7c00000000000000), out)
+	}
+
+	try := 10000
+	if testing.Short() {
+		try = 100
+	}
+	for ; try > 0; try-- {
+		f64 := math.Float64frombits(rand.Uint64())
+		if math.IsNaN(f64) || math.IsInf(f64, 0) {
+			continue
+		}
+		// at most 17 digits, which decimal128 has room for.
+		str := strconv.FormatFloat(f64, 'g', -1, 64)
+		d128, err := ParseDecimal128(str)
+		if out := Decimal128ToFloat(d128); out != f64 || err != nil {
+			t.Fatalf("Decimal128ToFloat(ParseDecimal128(%q)) = %v, %v want %v", str, out, err, f64)
+		}
+		// 16 digits, which decimal64 has room for.
+		str = strconv.FormatFloat(f64, 'e', 15, 64)
+		d64, err := ParseDecimal64(str)
+		want, _ := strconv.ParseFloat(str, 64)
+		if out := Decimal64ToFloat(d64); out != want || err != nil {
+			t.Fatalf("Decimal64ToFloat(ParseDecimal64(%q)) = %v, %v want %v", str, out, err, want)
+		}
+	}
+}
@@ -8,19 +8,79 @@ var (
 	fiv = big.NewInt(5)
 )
 
-func bigParseFloat(num string, extend int) (uint64, int, error) {
-	const fnc = "ParseFloat"
-	width2 := 32 << extend
-	width10 := 10 << extend
+// bigParseFloat converts num, which is already validated by the fast-path
+// and ends exactly where the number does. it returns whether the result is
+// inexact, and the error as is like pack.
+func bigParseFloat[T text](num T, p *Parser, f format) (uint64, bool, error) {
+	// pack takes at most 64 bits.
+	mant, exp, trunc, sign := bigScale(num, p, f, 64)
+	return f.pack(mant.Uint64(), exp, trunc, sign, p)
+}
+
+// bigScale reads num like bigParseFloat, and returns the sign and the number
+// as mant*2^exp, where mant has at most width bits, and at least width-1 of
+// them when it's not exact. trunc reports whether non-zero bits were cut off.
+func bigScale[T text](num T, p *Parser, f format, width int) (*big.Int, int, bool, int) {
+	// log10(2)*(1023+52) ~= 323 for binary64.
+	// max exponent + subnormal range + log10(2)*64.
+	mant, exp10, _, sign := bigRead(num, p, (f.bias+f.prec)*3/10+20)
+	mant, exp, trunc := bigPow10(mant, exp10, width)
+	return mant, exp, trunc, sign
+}
+
+// bigPow10 returns mant*10^exp10 as mant*2^exp, where mant has at most width
+// bits like bigScale. mant is reused for the result.
+func bigPow10(mant *big.Int, exp10 int, width int) (*big.Int, int, bool) {
+	var temp big.Int
+	exp := exp10
+	abs := big.NewInt(0)
+	abs.SetUint64(uint64(max(exp10, -exp10)))
+	// in .Exp(), If m == nil or m == 0, z = x**y.
+	temp.Exp(fiv, abs, nil)
+	var trunc bool
+	if exp10 >= 0 {
+		mant.Mul(mant, &temp)
+		log := mant.BitLen() - width
+		trunc = int(mant.TrailingZeroBits()) < log
+		if log > 0 {
+			mant.Rsh(mant, uint(log))
+			exp += log
+		}
+	} else {
+		var rem big.Int
+		log := temp.BitLen() - mant.BitLen() + width
+		if log > 0 {
+			mant.Lsh(mant, uint(log))
+			exp -= log
+		}
+		mant.DivMod(mant, &temp, &rem)
+		trunc = rem.Sign() != 0 // .Sign() == 0 only for 0.
+	}
+
+	// the quotient above can have one more bit than width.
+	if log := mant.BitLen() - width; log > 0 {
+		if int(mant.TrailingZeroBits()) < log {
+			trunc = true
+		}
+		mant.Rsh(mant, uint(log))
+		exp += log
+	}
+	return mant, exp, trunc
+}
+
+// bigRead reads num, which is validated by the fast-path and ends where the
+// number does, into mant*10^exp, or mant*2^exp for hexadecimals, exactly.
+// the exponent is kept in -limit..limit, in addition to the length of mant.
+func bigRead[T text](num T, p *Parser, limit int) (*big.Int, int, bool, int) {
 	var (
-		sign  int
-		mant  big.Int
-		exp10 int
+		sign int
+		mant big.Int
+		exp  int
 	)
 
 	var offset int
 	if offset >= len(num) {
-		panic("bigParseFloat was called with empty string")
+		panic("bigRead was called with empty string")
 	} else if num[offset] == '+' {
 		offset++
 	} else if num[offset] == '-' {
@@ -28,16 +88,48 @@ func bigParseFloat(num string, extend int) (uint64, int, error) {
 		sign = 1
 	}
 
-	// Infs and NaNs are checked in fast-path.
 	var temp big.Int
 	var point bool
+	if offset+1 < len(num) && num[offset] == '0' && num[offset+1]|0x20 == 'x' && !p.NoHex {
+		for offset += len("0x"); offset < len(num); offset++ {
+			char := num[offset]
+			if char == '.' && !point {
+				point = true
+				continue
+			}
+			if char == '_' {
+				continue
+			}
+			var dig uint64
+			switch {
+			case char-'0' <= '9'-'0':
+				dig = uint64(char - '0')
+			case char|0x20-'a' <= 'f'-'a':
+				dig = uint64(char|0x20-'a') + 10
+			default:
+				// 'p' is required after hexadecimals.
+				// log2(10) < 4 for the limit in decimal.
+				limit = (limit + mant.BitLen()) * 4
+				return &mant, wideExp(num[offset+1:], exp, limit, mant.Sign() == 0), true, sign
+			}
+			if point {
+				exp -= 4
+			}
+			mant.Lsh(&mant, 4)
+			temp.SetUint64(dig)
+			mant.Add(&mant, &temp)
+		}
+	}
+
+	// Infs and NaNs are checked in fast-path.
+	mark, sep, _ := p.notation()
 	for ; offset < len(num); offset++ {
 		char := num[offset]
-		if char == '.' && !point {
+		if char == mark && !point {
 			point = true
 			continue
 		}
-		if char == '_' {
+		if char == sep {
 			// also checked by fast-path.
 			continue
 		}
@@ -46,7 +138,7 @@ func bigParseFloat(num string, extend int) (uint64, int, error) {
 			break
 		}
 		if point {
-			exp10--
+			exp--
 		}
 		// * 10 might compile down to multiplication unlike
 		// word size math, so we manually make shifts.
@@ -59,118 +151,8 @@ func bigParseFloat(num string, extend int) (uint64, int, error) {
 	}
 
 	if offset < len(num) && num[offset]|0x20 == 'e' {
-		// log10(2)*1024 ~= 308.
-		// max exponent + "mant" length + log10(2)*width.
-		limit := 308 + int(mant.BitLen()) + width10
-		var shift int
-		var esign bool
-		offset++
-		if offset >= len(num) {
-			return 0, 0, errorSyntax(fnc, num)
-		} else if num[offset] == '+' {
-			offset++
-		} else if num[offset] == '-' {
-			offset++
-			esign = true
-		}
-		for ; offset < len(num); offset++ {
-			char := num[offset]
-			if char == '_' {
-				continue
-			}
-			char -= '0'
-			if char > '9'-'0' {
-				break
-			}
-			// definitely an overflow/
-			if esign && exp10-shift < -limit || !esign && exp10+shift > limit {
-				continue
-			}
-			shift = shift*10 + int(char)
-		}
-		if esign {
-			exp10 -= shift
-		} else {
-			exp10 += shift
-		}
-	}
-
-	exp := exp10
-	abs := big.NewInt(0)
-	abs.SetUint64(uint64(max(exp10, -exp10)))
-	// in .Exp(), If m == nil or m == 0, z = x**y.
-	temp.Exp(fiv, abs, nil)
-	var trunc bool
-	if exp10 >= 0 {
-		mant.Mul(&mant, &temp)
-		log := mant.BitLen() - width2
-		trunc = int(mant.TrailingZeroBits()) < log
-		if log > 0 {
-			mant.Rsh(&mant, uint(log))
-			exp += log
-		}
-	} else {
-		var rem big.Int
-		log := temp.BitLen() - mant.BitLen() + width2
-		if log > 0 {
-			mant.Lsh(&mant, uint(log))
-			exp -= log
-		}
-		mant.DivMod(&mant, &temp, &rem)
-		trunc = rem.Sign() != 0 // .Sign() == 0 only for 0.
-	}
-
-	var prec, bias int
-	switch width2 {
-	case 64:
-		prec = 52
-		bias = 1023
-	case 32:
-		prec = 23
-		bias = 127
-	}
-
-	log := mant.BitLen() - prec - 1 - 1
-	exp += log + bias + prec + 1
-	if log > 0 {
-		// TODO(?): find a better way to find truncated bits.
-		// is TrailingZeros enough?
-		if int(mant.TrailingZeroBits()) < log {
-			trunc = true
-		}
-		mant.Rsh(&mant, uint(log))
-	} else {
-		mant.Lsh(&mant, uint(-log))
-	}
-	if exp <= 0 {
-		if int(mant.TrailingZeroBits()) < 1-exp {
-			trunc = true
-		}
-		mant.Rsh(&mant, uint(1-exp))
-		exp = 0
-	}
-	bit := mant.Uint64()
-	// see hex.go for explanation of rounding.
-	round := bit & 1
-	if round != 0 && !trunc {
-		round &= bit >> 1
-	}
-	bit += round
-	bit >>= 1
-	if bit>>prec != 0 && exp == 0 {
-		exp++
-	}
-	carry := bit >> (prec + 1)
-	bit >>= carry
-	exp += int(carry)
-	bit &= 1<<prec - 1
-	if extend == 1 && exp >= 0x7ff {
-		return uint64(0x7ff)<<prec | uint64(sign)<<(width2-1), offset, errorRange(fnc, num)
-	}
-	if extend == 0 && exp >= 0x0ff {
-		return uint64(0x0ff)<<prec | uint64(sign)<<(width2-1), offset, errorRange(fnc, num)
+		// "mant" length is added, which is at least the number of digits.
+		exp = wideExp(num[offset+1:], exp, limit+mant.BitLen(), mant.Sign() == 0)
 	}
-	bit |= uint64(exp) << prec
-	bit |= uint64(sign) << (width2 - 1)
-	return bit, offset, nil
+	return &mant, exp, false, sign
 }
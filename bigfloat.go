@@ -0,0 +1,120 @@
+package refloat
+
+import (
+	"math"
+	"math/big"
+)
+
+// ParseBigFloat converts num to a *big.Float of prec bits, correctly rounded
+// with mode. num has the same syntax as for ParseFloat. If prec is 0, it's
+// changed to 64, like big.Float.Parse does.
+//
+// big.Float has a far wider exponent range than float64, so numbers overflow
+// only beyond it, where the result is ±Inf and ErrRange. NaN can't be held by
+// big.Float, and it's reported as ErrRange with a nil result.
+func ParseBigFloat(num string, prec uint, mode big.RoundingMode) (*big.Float, error) {
+	return std.ParseBigFloat(num, prec, mode)
+}
+
+// ParseBigFloat is like the package-level ParseBigFloat, but with the syntax of p.
+func (p *Parser) ParseBigFloat(num string, prec uint, mode big.RoundingMode) (*big.Float, error) {
+	const fnc = "ParseBigFloat"
+	if prec == 0 {
+		prec = 64
+	}
+	f64, err := parseAll(num, fnc, p, toOdd)
+	if err != nil && err.(*NumError).Err == ErrSyntax {
+		return nil, err
+	}
+	if f64 != f64 {
+		return nil, errorRange(fnc, num)
+	}
+
+	val := new(big.Float).SetPrec(prec).SetMode(mode)
+	// Infs here are written as such, since rounding to odd never overflows
+	// to Inf. zeros are exact, since it never rounds to zero either.
+	if err == nil && (math.IsInf(f64, 0) || f64 == 0) {
+		return val.SetFloat64(f64), nil
+	}
+	// subnormals have less precision, and overflows are the largest finite number.
+	if err == nil && prec <= 53-2 && math.Abs(f64) >= 0x1p-1022 {
+		return val.SetFloat64(f64), nil
+	}
+	if prec <= 113-2 {
+		odd := p.with(toOdd)
+		hi, lo, err := parseWide(num, &odd, binary128, fnc)
+		if exp := int(hi >> 48 & 0x7fff); err == nil && exp != 0 && exp != 0x7fff {
+			mant := new(big.Int).SetUint64(hi&(1<<48-1) | 1<<48)
+			mant.Lsh(mant, 64)
+			mant.Add(mant, new(big.Int).SetUint64(lo))
+			if hi>>63 != 0 {
+				mant.Neg(mant)
+			}
+			val.SetInt(mant)
+			return val.SetMantExp(val, exp-16383-112), nil
+		}
+	}
+
+	// log10(2) < 1/3 for the exponent range of big.Float.
+	mant, exp, hex, sign := bigRead(num, p, big.MaxExp/3)
+	if hex {
+		if sign != 0 {
+			mant.Neg(mant)
+		}
+		val.SetInt(mant)
+		val.SetMantExp(val, exp)
+	} else {
+		val = bigRound(mant, exp, sign, prec, mode)
+	}
+	if val.IsInf() {
+		return val, errorRange(fnc, num)
+	}
+	return val, nil
+}
+
+// bigRound returns mant*10^exp10 rounded to prec bits with mode, which is
+// negative for sign 1. the lower and upper bounds of mant*5^exp10 are made
+// with more bits each time, until both of them round to the same number.
+// it always ends, since they're exact with enough bits when the product is
+// dyadic, and only then it can be on the boundary of rounding.
+func bigRound(mant *big.Int, exp10 int, sign int, prec uint, mode big.RoundingMode) *big.Float {
+	abs := uint64(max(exp10, -exp10))
+	for extra := uint(64); ; extra *= 2 {
+		work := prec + extra
+		var lo, hi big.Float
+		lo.SetPrec(work).SetMode(big.ToNegativeInf).SetInt(mant)
+		hi.SetPrec(work).SetMode(big.ToPositiveInf).SetInt(mant)
+		if exp10 >= 0 {
+			lo.Mul(&lo, pow5(abs, work, big.ToNegativeInf))
+			hi.Mul(&hi, pow5(abs, work, big.ToPositiveInf))
+		} else {
+			lo.Quo(&lo, pow5(abs, work, big.ToPositiveInf))
+			hi.Quo(&hi, pow5(abs, work, big.ToNegativeInf))
+		}
+		if sign != 0 {
+			lo.Neg(&lo)
+			hi.Neg(&hi)
+		}
+		val := new(big.Float).SetPrec(prec).SetMode(mode).Set(&lo)
+		if val.Cmp(new(big.Float).SetPrec(prec).SetMode(mode).Set(&hi)) == 0 {
+			// 10^exp10 == 5^exp10*2^exp10, where the latter is exact.
+			return val.SetMantExp(val, exp10)
+		}
+	}
+}
+
+// pow5 returns 5^exp with prec bits, rounded with mode at each step.
+// for the directed modes, it's the bound of the exact power.
+func pow5(exp uint64, prec uint, mode big.RoundingMode) *big.Float {
+	pow := new(big.Float).SetPrec(prec).SetMode(mode).SetUint64(1)
+	base := new(big.Float).SetPrec(prec).SetMode(mode).SetUint64(5)
+	for ; exp != 0; exp >>= 1 {
+		if exp&1 != 0 {
+			pow.Mul(pow, base)
+		}
+		if exp > 1 {
+			base.Mul(base, base)
+		}
+	}
+	return pow
+}
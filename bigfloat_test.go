@@ -0,0 +1,92 @@
+package refloat_test
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+func TestParseBigFloat(t *testing.T) {
+	for _, test := range []struct {
+		inp  string
+		prec uint
+		mode big.RoundingMode
+		out  string
+		err  error
+	}{
+		{"1", 1, big.ToNearestEven, "0x.8p+1", nil},
+		{"0.1", 53, big.ToNearestEven, "0x.ccccccccccccdp-3", nil},
+		{"0.1", 53, big.ToZero, "0x.ccccccccccccc8p-3", nil},
+		{"-0.1", 53, big.ToNegativeInf, "-0x.ccccccccccccdp-3", nil},
+		{"0.1", 0, big.ToNearestEven, "0x.cccccccccccccccdp-3", nil},
+		{"0.1", 100, big.ToNearestEven, "0x.ccccccccccccccccccccccccdp-3", nil},
+		{"0.1", 200, big.AwayFromZero, "0x.cccccccccccccccccccccccccccccccccccccccccccccccccdp-3", nil},
+		// ties.
+		{"2.5", 2, big.ToNearestEven, "0x.8p+2", nil},
+		{"2.5", 2, big.ToNearestAway, "0x.cp+2", nil},
+		{"1606938044258990275541962092341162602522202993782792835301377", 200, big.ToNearestEven, "0x.8p+201", nil},
+		{"1606938044258990275541962092341162602522202993782792835301377", 200, big.ToPositiveInf, "0x.80000000000000000000000000000000000000000000000001p+201", nil},
+		{"0x1.8p0", 1, big.ToNearestEven, "0x.8p+2", nil},
+		// 2^-160 is a tie at 160 bits.
+		{"0x1.0000000000000000000000000000000000000001p0", 160, big.ToNearestEven, "0x.8p+1", nil},
+		{"0x1.0000000000000000000000000000000000000001p0", 161, big.ToNearestEven, "0x.80000000000000000000000000000000000000008p+1", nil},
+		{"1_000", 64, big.ToNearestEven, "0x.fap+10", nil},
+		// far beyond float64.
+		{"1e1000", 10, big.ToNearestEven, "0x.f38p+3322", nil},
+		{"1e-1000", 10, big.ToNearestEven, "0x.868p-3321", nil},
+		{"1e1000000000", 53, big.ToNearestEven, "+Inf", ErrRange},
+		{"-1e-1000000000", 53, big.ToNearestEven, "-0", nil},
+		{"-0", 53, big.ToNearestEven, "-0", nil},
+		{"-inf", 53, big.ToNearestEven, "-Inf", nil},
+		{"1x", 53, big.ToNearestEven, "", ErrSyntax},
+	} {
+		out, err := ParseBigFloat(test.inp, test.prec, test.mode)
+		var str string
+		if out != nil {
+			str = out.Text('p', 0)
+		}
+		if str != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("ParseBigFloat(%q, %d, %v) = %s, %v want %s, %v", test.inp, test.prec, test.mode, str, err, test.out, test.err)
+		}
+	}
+	_, err := ParseBigFloat("nan", 53, big.ToNearestEven)
+	var num *NumError
+	if !errors.As(err, &num) || num.Func != "ParseBigFloat" || !errors.Is(err, ErrRange) {
+		t.Errorf("ParseBigFloat(%q) = _, %#v; want a *NumError for ParseBigFloat", "nan", err)
+	}
+}
+
+// big.Float rounds rationals correctly, for any precision.
+func TestBigFloatRounding(t *testing.T) {
+	try := 2000
+	if testing.Short() {
+		try = 200
+	}
+	modes := []big.RoundingMode{big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf}
+	for ; try > 0; try-- {
+		mant := new(big.Int).Rand(rand.New(rand.NewSource(int64(try))), new(big.Int).Lsh(big.NewInt(1), uint(rand.Intn(300)+1)))
+		exp := rand.Intn(800) - 400
+		str := mant.String() + "e" + big.NewInt(int64(exp)).String()
+		if try%2 == 0 {
+			str = "-" + str
+			mant.Neg(mant)
+		}
+		rat := new(big.Rat).SetInt(mant)
+		pow := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(exp, -exp))), nil))
+		if exp > 0 {
+			rat.Mul(rat, pow)
+		} else {
+			rat.Quo(rat, pow)
+		}
+		prec := uint(rand.Intn(300) + 1)
+		mode := modes[rand.Intn(len(modes))]
+		want := new(big.Float).SetPrec(prec).SetMode(mode).SetRat(rat)
+		out, err := ParseBigFloat(str, prec, mode)
+		if err != nil || out.Cmp(want) != 0 {
+			t.Fatalf("ParseBigFloat(%q, %d, %v) = %v, %v want %v", str, prec, mode, out, err, want.Text('p', 0))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package refloat
+
+import (
+	"math"
+	"math/big"
+)
+
+// ParseBoth is like ParseFloat32 and ParseFloat64 at once, but reads the
+// digits of decimal numbers only once. The float32 is rounded as if from
+// the digits, just like ParseFloat32, and never twice through the float64.
+//
+// The error is the one for the float64 if any, or else the one for the
+// float32, such as ErrRange for numbers that only overflow float32.
+func ParseBoth(num string) (float32, float64, error) {
+	return std.ParseBoth(num)
+}
+
+// ParseBoth is like the package-level ParseBoth, but with the options of p.
+func (p *Parser) ParseBoth(num string) (float32, float64, error) {
+	const fnc = "ParseBoth"
+	dec, done, f64, offset, err := scan64(num, p, limit64)
+	f64, err = whole(num, f64, offset, err, fnc)
+	if err != nil && err.(*NumError).Err == ErrSyntax {
+		return 0, 0, err
+	}
+
+	if done {
+		// Infs, NaNs and hexadecimals are read once more, which is rare,
+		// so they are the same as ParseFloat32 in every way.
+		f32, _, err32 := parseFloat32(num, p)
+		if err == nil && err32 != nil {
+			err = err32
+			err.(*NumError).Func = fnc
+		}
+		return f32, f64, err
+	}
+
+	u64, ok, inexact, err64 := compose64(dec.mant, dec.exp10, dec.trunc, dec.sign, p)
+	if !ok {
+		u64, inexact, err64 = bigParseFloat(num, p, binary64)
+	}
+	err64 = p.check(inexact, err64)
+	// the float64 narrows to the same float32 as the digits do, unless it
+	// was rounded to nearest onto a tie of binary32, or Exact has to tell
+	// it's rounded. those are rounded to odd once more, see toOdd.
+	odd64 := u64
+	near := p.Rounding == big.ToNearestEven || p.Rounding == big.ToNearestAway
+	if p.Exact && inexact || !p.Exact && near && tie32(u64) {
+		odd := p.with(toOdd)
+		odd64, ok, _, _ = compose64(dec.mant, dec.exp10, dec.trunc, dec.sign, &odd)
+		if !ok {
+			odd64, _, _ = bigParseFloat(num, &odd, binary64)
+		}
+	}
+	u32, err32 := binary32.narrow(odd64, p)
+	if err64 == nil {
+		err64 = err32
+	}
+	return math.Float32frombits(uint32(u32)), math.Float64frombits(u64), errorOf(fnc, num, err64)
+}
+
+// tie32 reports whether the float64 bits are exactly halfway between two
+// float32s, subnormals included.
+func tie32(u64 uint64) bool {
+	exp := int(u64 >> 52 & 0x7ff)
+	if exp == 0 {
+		// way below the smallest float32.
+		return false
+	}
+	mant := u64&(1<<52-1) | 1<<52
+	// the bits below binary32, which has more of them for subnormals.
+	drop := 52 - 23 + max(1-(exp-1023+127), 0)
+	if drop > 53 {
+		return false
+	}
+	return mant&(1<<drop-1) == 1<<(drop-1)
+}
@@ -0,0 +1,81 @@
+package refloat_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+func TestParseBoth(t *testing.T) {
+	for _, test := range []struct {
+		inp string
+		f32 float32
+		f64 float64
+		err error
+	}{
+		{"1", 1, 1, nil},
+		{"-0.1", -0.1, -0.1, nil},
+		// 1+2^-24+2^-60, where the float64 is a tie for float32.
+		{"1.000000059604644776257986737988403547205962240695953369140625", 1.0000001, 1.0000000596046448, nil},
+		// 2.5*2^-149+2^-210, where the float64 is a tie for float32 subnormals.
+		{"3.503246160812042677917040293953417448038942359943254687262812523076154184556557423129651997666571869256244611633370470405953768511242429894991801120340824127197265625e-45", 4e-45, 3.5032461608120427e-45, nil},
+		{"0x1.000001p0", 1, 1.0000000596046448, nil},
+		{"0x1.0000010000001p0", 1.0000001, 1.0000000596046450, nil},
+		{"1e39", float32(math.Inf(1)), 1e39, ErrRange},
+		{"1e-50", 0, 1e-50, nil},
+		{"1e400", float32(math.Inf(1)), math.Inf(1), ErrRange},
+		{"-inf", float32(math.Inf(-1)), math.Inf(-1), nil},
+		{"1_000", 1000, 1000, nil},
+		{"1x", 0, 0, ErrSyntax},
+		{"0x1p", 0, 0, ErrSyntax},
+	} {
+		f32, f64, err := ParseBoth(test.inp)
+		if f32 != test.f32 || f64 != test.f64 || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("ParseBoth(%q) = %v, %v, %v want %v, %v, %v", test.inp, f32, f64, err, test.f32, test.f64, test.err)
+		}
+	}
+	_, _, err := ParseBoth("1.5e")
+	var num *NumError
+	if !errors.As(err, &num) || num.Func != "ParseBoth" || !errors.Is(err, ErrExponent) {
+		t.Errorf("ParseBoth(%q) = _, _, %#v; want a *NumError for ParseBoth", "1.5e", err)
+	}
+	if f32, f64, _ := ParseBoth("nan"); f32 == f32 || f64 == f64 {
+		t.Errorf("ParseBoth(%q) = %v, %v want NaN, NaN", "nan", f32, f64)
+	}
+}
+
+// the results should be the same as ParseFloat32 and ParseFloat64.
+func TestParseBothSame(t *testing.T) {
+	try := 20000
+	if testing.Short() {
+		try = 1000
+	}
+	modes := []big.RoundingMode{big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf}
+	for ; try > 0; try-- {
+		par := Parser{Rounding: modes[rand.Intn(len(modes))], Exact: rand.Intn(4) == 0}
+		var str string
+		switch try % 3 {
+		case 0:
+			str = strconv.FormatFloat(math.Float64frombits(rand.Uint64()), 'g', -1, 64)
+		case 1:
+			str = strconv.FormatFloat(math.Float64frombits(rand.Uint64()), 'x', -1, 64)
+		default:
+			str = strconv.FormatUint(rand.Uint64()>>rand.Intn(64), 10) + strconv.FormatUint(rand.Uint64(), 10) + "e" + strconv.Itoa(rand.Intn(100)-70)
+		}
+		f32, f64, err := par.ParseBoth(str)
+		w32, err32 := par.ParseFloat32(str)
+		w64, err64 := par.ParseFloat64(str)
+		want := err64
+		if want == nil {
+			want = err32
+		}
+		if math.Float32bits(f32) != math.Float32bits(w32) || math.Float64bits(f64) != math.Float64bits(w64) || errors.Unwrap(err) != errors.Unwrap(want) {
+			t.Fatalf("%+v.ParseBoth(%q) = %v, %v, %v want %v, %v, %v", par, str, f32, f64, err, w32, w64, want)
+		}
+	}
+}
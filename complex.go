@@ -0,0 +1,91 @@
+package refloat
+
+// ParseComplex converts the string num to a complex number
+// with the precision specified by size: 64 for complex64, or 128 for complex128.
+// When size=64, the result still has type complex128, but it will be
+// convertible to complex64 without changing its value.
+//
+// The number represented by num must be of the form N, Ni, or N±Ni, where N stands
+// for a floating-point number as recognized by ParseFloat, and i is the imaginary
+// component. If the second N is unsigned, a + sign is required between the two components
+// as indicated by the ±. If the second N is NaN, only a + sign is accepted.
+// The form may be parenthesized and cannot contain any spaces.
+// The resulting complex number consists of the two components converted by ParseFloat.
+//
+// The errors that ParseComplex returns have concrete type *NumError
+// and include err.Num = num.
+//
+// If num is not syntactically well-formed, ParseComplex returns err.Err = ErrSyntax.
+//
+// If num is syntactically well-formed but either component is more than 1/2 ULP
+// away from the largest floating point number of the given component's size,
+// ParseComplex returns err.Err = ErrRange and c = ±Inf for the respective component.
+func ParseComplex(num string, size int) (complex128, error) {
+	const fnc = "ParseComplex"
+	// each of the components has half the size.
+	half := 64
+	if size == 64 {
+		half = 32
+	}
+
+	// start is where str begins in num, for the offsets of errors.
+	str, start := num, 0
+	if len(str) >= 2 && str[0] == '(' && str[len(str)-1] == ')' {
+		str, start = str[1:len(str)-1], 1
+	}
+
+	// pending holds a range error of the real part, which
+	// should be reported only if the rest is well-formed.
+	var pending error
+	re, read, err := parseFloat(str, half, &std)
+	if err != nil {
+		if err := err.(*NumError); err.Err != ErrRange {
+			return 0, errorSyntax(fnc, num, start+err.Offset, err.Reason)
+		}
+		pending = errorRange(fnc, num)
+	}
+	str, start = str[read:], start+read
+
+	if len(str) == 0 {
+		return complex(re, 0), pending
+	}
+
+	switch str[0] {
+	case '+':
+		// consume '+' so that "+NaNi" is accepted,
+		// but keep it for "++" to report the error.
+		if len(str) > 1 && str[1] != '+' {
+			str, start = str[1:], start+1
+		}
+	case '-':
+		// the sign belongs to the imaginary part.
+	case 'i':
+		// the real part was the imaginary part.
+		if len(str) == 1 {
+			return complex(0, re), pending
+		}
+		return 0, errorSyntax(fnc, num, start+1, ErrTrailing)
+	default:
+		return 0, errorSyntax(fnc, num, start, ErrTrailing)
+	}
+
+	im, read, err := parseFloat(str, half, &std)
+	if err != nil {
+		if err := err.(*NumError); err.Err != ErrRange {
+			return 0, errorSyntax(fnc, num, start+err.Offset, err.Reason)
+		}
+		pending = errorRange(fnc, num)
+	}
+	str, start = str[read:], start+read
+	if len(str) == 0 {
+		return 0, errorSyntax(fnc, num, start, ErrImaginary)
+	}
+	if str != "i" {
+		// past 'i' if there is one.
+		if str[0] == 'i' {
+			start++
+		}
+		return 0, errorSyntax(fnc, num, start, ErrTrailing)
+	}
+	return complex(re, im), pending
+}
@@ -0,0 +1,156 @@
+package refloat
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// A Decimal is a decimal number as written, read by ParseDecimal.
+// Its value is Mant*10^Exp10, or -Mant*10^Exp10 if Neg is set,
+// when it's not Truncated.
+type Decimal struct {
+	Neg   bool   // the sign, which is kept for zeros too.
+	Mant  uint64 // the leading significant digits that fit, 19 of them at least.
+	Exp10 int    // the exponent of the last digit in Mant.
+
+	// Truncated reports whether non-zero digits were dropped after Mant.
+	// dropped zeros are exact, and added to Exp10 instead.
+	Truncated bool
+	// Digits is the number of significant digits, from the first non-zero
+	// digit to the last digit, including the ones dropped. It's 0 for zeros.
+	Digits int
+	// num[Start:End] is the mantissa as written, which has the digits,
+	// the decimal point and the separators, but not the sign or the exponent.
+	Start, End int
+}
+
+// ParseDecimal reads num, which has the same syntax as for ParseFloat
+// without hexadecimals, into a Decimal, without converting it to binary.
+//
+// Infs and NaNs are reported as ErrRange, and so are exponents beyond
+// ±2^31-1, with the zero Decimal.
+func ParseDecimal(num string) (Decimal, error) {
+	return std.ParseDecimal(num)
+}
+
+// ParseDecimal is like the package-level ParseDecimal, but with the syntax of p.
+func (p *Parser) ParseDecimal(num string) (Decimal, error) {
+	const fnc = "ParseDecimal"
+	// the syntax is the one of ParseFloat without hexadecimals.
+	chk := *p
+	chk.NoHex = true
+	raw, done, _, read, err := scan64(num, &chk, math.MaxInt32)
+	_, err = whole(num, 0, read, err, fnc)
+	if err != nil && err.(*NumError).Err == ErrSyntax {
+		return Decimal{}, err
+	}
+	if done || raw.cut {
+		return Decimal{}, errorRange(fnc, num)
+	}
+
+	// the digits are the ones in Mant and the ones dropped after it.
+	dec := Decimal{
+		Neg:       raw.sign != 0,
+		Mant:      raw.mant,
+		Exp10:     raw.exp10,
+		Truncated: raw.trunc,
+		Digits:    raw.drop,
+		Start:     raw.begin,
+		End:       raw.end,
+	}
+	for mant := dec.Mant; mant != 0; mant /= 10 {
+		dec.Digits++
+	}
+	return dec, nil
+}
+
+// FromDecimal returns mant*10^exp10, negated if neg is set, as the nearest
+// floating-point number of size like ParseFloat, without formatting it as
+// a string first. The errors are the same as ParseFloat, with err.Num
+// formatted as mant, 'e' and exp10.
+func FromDecimal(neg bool, mant uint64, exp10 int, size int) (float64, error) {
+	return std.FromDecimal(neg, mant, exp10, size)
+}
+
+// FromDecimalBig is like FromDecimal, but for mantissas that don't fit
+// in uint64. The value is negated once more if mant is negative.
+func FromDecimalBig(neg bool, mant *big.Int, exp10 int, size int) (float64, error) {
+	return std.FromDecimalBig(neg, mant, exp10, size)
+}
+
+// FromDecimal is like the package-level FromDecimal, but rounds with the
+// options of p, such as Rounding and Overflow.
+func (p *Parser) FromDecimal(neg bool, mant uint64, exp10 int, size int) (float64, error) {
+	const fnc = "FromDecimal"
+	f64, err := fromDecimal(mant, nil, exp10, neg, size, p)
+	if err != nil {
+		num := strconv.FormatUint(mant, 10) + "e" + strconv.Itoa(exp10)
+		if neg {
+			num = "-" + num
+		}
+		return f64, errorOf(fnc, num, err)
+	}
+	return f64, nil
+}
+
+// FromDecimalBig is like the package-level FromDecimalBig, but rounds with
+// the options of p, such as Rounding and Overflow.
+func (p *Parser) FromDecimalBig(neg bool, mant *big.Int, exp10 int, size int) (float64, error) {
+	const fnc = "FromDecimalBig"
+	neg = neg != (mant.Sign() < 0)
+	abs := new(big.Int).Abs(mant)
+	var f64 float64
+	var err error
+	if abs.IsUint64() {
+		f64, err = fromDecimal(abs.Uint64(), nil, exp10, neg, size, p)
+	} else {
+		f64, err = fromDecimal(0, abs, exp10, neg, size, p)
+	}
+	if err != nil {
+		num := abs.String() + "e" + strconv.Itoa(exp10)
+		if neg {
+			num = "-" + num
+		}
+		return f64, errorOf(fnc, num, err)
+	}
+	return f64, nil
+}
+
+// fromDecimal converts mant*10^exp10, or wide*10^exp10 if wide is not nil,
+// to size. wide is reused. the error is returned as is, like bigParseFloat.
+func fromDecimal(mant uint64, wide *big.Int, exp10 int, neg bool, size int, p *Parser) (float64, error) {
+	var sign int
+	if neg {
+		sign = 1
+	}
+	if size == 32 {
+		odd := p.with(toOdd)
+		f64, _ := fromDecimal(mant, wide, exp10, neg, 64, &odd)
+		u64, err := binary32.narrow(math.Float64bits(f64), p)
+		return float64(math.Float32frombits(uint32(u64))), err
+	}
+
+	var (
+		u64     uint64
+		inexact bool
+		err     error
+	)
+	if wide == nil {
+		// beyond these, the result is Inf or the smallest subnormal at
+		// most, just like parseFloat64. mant has 20 digits at most.
+		exp10 = min(max(exp10, -limit64), limit64)
+		var ok bool
+		u64, ok, inexact, err = compose64(mant, exp10, false, sign, p)
+		if ok {
+			return math.Float64frombits(u64), p.check(inexact, err)
+		}
+		wide = new(big.Int).SetUint64(mant)
+	} else {
+		// the length of wide in bits is more than the digits.
+		exp10 = min(max(exp10, -308-20-wide.BitLen()), 308+20)
+	}
+	wide, exp, trunc := bigPow10(wide, exp10, 64)
+	u64, inexact, err = binary64.pack(wide.Uint64(), exp, trunc, sign, p)
+	return math.Float64frombits(u64), p.check(inexact, err)
+}
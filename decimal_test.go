@@ -0,0 +1,134 @@
+package refloat_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+func TestDecimal(t *testing.T) {
+	for _, test := range []struct {
+		inp string
+		out Decimal
+		err error
+	}{
+		{"1", Decimal{Mant: 1, Digits: 1, Start: 0, End: 1}, nil},
+		{"-1.20", Decimal{Neg: true, Mant: 120, Exp10: -2, Digits: 3, Start: 1, End: 5}, nil},
+		{"+0.00120e5", Decimal{Mant: 120, Exp10: 0, Digits: 3, Start: 1, End: 8}, nil},
+		{"-0", Decimal{Neg: true, Digits: 0, Start: 1, End: 2}, nil},
+		{"0e-10", Decimal{Exp10: -10, Start: 0, End: 1}, nil},
+		{"1_000.5", Decimal{Mant: 10005, Exp10: -1, Digits: 5, Start: 0, End: 7}, nil},
+		{".5", Decimal{Mant: 5, Exp10: -1, Digits: 1, Start: 0, End: 2}, nil},
+		// the digits are kept while they fit, 19 of them at least.
+		{"12345678901234567890123", Decimal{Mant: 12345678901234567890, Exp10: 3, Truncated: true, Digits: 23, End: 23}, nil},
+		{"12345678901234567890000", Decimal{Mant: 12345678901234567890, Exp10: 3, Digits: 23, End: 23}, nil},
+		{"0.000000000000000000000001234567890123456789012e-3", Decimal{Mant: 12345678901234567890, Exp10: -46, Truncated: true, Digits: 22, End: 47}, nil},
+		{"1e2147483647", Decimal{Mant: 1, Exp10: 2147483647, Digits: 1, End: 1}, nil},
+		{"1e2147483648", Decimal{}, ErrRange},
+		{"1e-99999999999999999999", Decimal{}, ErrRange},
+		// these overflowed int on 32-bit.
+		{"0.1e2147483648", Decimal{Mant: 1, Exp10: 2147483647, Digits: 1, End: 3}, nil},
+		{"-1e-2147483648", Decimal{}, ErrRange},
+		{"1e9999999999", Decimal{}, ErrRange},
+		{"1e21474836470", Decimal{}, ErrRange},
+		{"inf", Decimal{}, ErrRange},
+		{"nan", Decimal{}, ErrRange},
+		{"0x1p0", Decimal{}, ErrSyntax},
+		{"1e", Decimal{}, ErrSyntax},
+		{"1x", Decimal{}, ErrSyntax},
+	} {
+		out, err := ParseDecimal(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("ParseDecimal(%q) = %+v, %v want %+v, %v", test.inp, out, err, test.out, test.err)
+		}
+	}
+	_, err := ParseDecimal("1.5e")
+	var num *NumError
+	if !errors.As(err, &num) || num.Func != "ParseDecimal" || !errors.Is(err, ErrExponent) {
+		t.Errorf("ParseDecimal(%q) = _, %#v; want a *NumError for ParseDecimal", "1.5e", err)
+	}
+}
+
+func TestDecimalLocale(t *testing.T) {
+	par := Parser{Locale: Locale{Point: ',', Group: '.'}}
+	want := Decimal{Mant: 100050, Exp10: -2, Digits: 6, End: 8}
+	if out, err := par.ParseDecimal("1.000,50"); out != want || err != nil {
+		t.Errorf("%+v.ParseDecimal(%q) = %+v, %v want %+v, <nil>", par, "1.000,50", out, err, want)
+	}
+}
+
+func TestFromDecimal(t *testing.T) {
+	for _, test := range []struct {
+		neg  bool
+		mant uint64
+		exp  int
+		size int
+		out  float64
+		err  error
+	}{
+		{false, 1, 0, 64, 1, nil},
+		{true, 1, -1, 64, -0.1, nil},
+		{false, 18446744073709551615, 0, 64, 18446744073709551615, nil},
+		{false, 9007199254740993, 0, 64, 9007199254740992, nil},
+		{false, 17976931348623157, 292, 64, math.MaxFloat64, nil},
+		{true, 1, 309, 64, math.Inf(-1), ErrRange},
+		{false, 1, math.MaxInt32, 64, math.Inf(1), ErrRange},
+		{false, 5, -324, 64, 5e-324, nil},
+		{false, 1, math.MinInt32, 64, 0, nil},
+		{true, 0, math.MaxInt32, 64, math.Copysign(0, -1), nil},
+		{false, 1, -1, 32, float64(float32(0.1)), nil},
+		{false, 34028235677973366, 22, 32, math.MaxFloat32, nil},
+		{false, 34028236, 31, 32, math.Inf(1), ErrRange},
+	} {
+		out, err := FromDecimal(test.neg, test.mant, test.exp, test.size)
+		if out != test.out || math.Signbit(out) != math.Signbit(test.out) || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("FromDecimal(%v, %d, %d, %d) = %v, %v want %v, %v", test.neg, test.mant, test.exp, test.size, out, err, test.out, test.err)
+		}
+	}
+	_, err := FromDecimal(true, 1, 400, 64)
+	var num *NumError
+	if !errors.As(err, &num) || num.Func != "FromDecimal" || num.Num != "-1e400" {
+		t.Errorf("FromDecimal(true, 1, 400, 64) = _, %#v; want a *NumError for -1e400", err)
+	}
+}
+
+// the results should be the same as parsing the formatted string.
+func TestFromDecimalParse(t *testing.T) {
+	try := 20000
+	if testing.Short() {
+		try = 1000
+	}
+	modes := []big.RoundingMode{big.ToNearestEven, big.ToNearestAway, big.ToZero, big.AwayFromZero, big.ToNegativeInf, big.ToPositiveInf}
+	for ; try > 0; try-- {
+		par := Parser{Rounding: modes[rand.Intn(len(modes))], Exact: rand.Intn(4) == 0, Underflow: rand.Intn(4) == 0}
+		neg := rand.Intn(2) == 0
+		mant := rand.Uint64() >> rand.Intn(64)
+		exp := rand.Intn(800) - 400
+		size := 32 << rand.Intn(2)
+		wide := new(big.Int).SetUint64(mant)
+		if try%4 == 0 {
+			wide.Lsh(wide, 64)
+			wide.Add(wide, new(big.Int).SetUint64(rand.Uint64()))
+		}
+		str := wide.String() + "e" + strconv.Itoa(exp)
+		if neg {
+			str = "-" + str
+		}
+		want, werr := par.ParseFloat(str, size)
+		var out float64
+		var err error
+		if try%4 == 0 {
+			out, err = par.FromDecimalBig(neg, wide, exp, size)
+		} else {
+			out, err = par.FromDecimal(neg, mant, exp, size)
+		}
+		if math.Float64bits(out) != math.Float64bits(want) || errors.Unwrap(err) != errors.Unwrap(werr) {
+			t.Fatalf("%+v.FromDecimal(%q, %d) = %v, %v want %v, %v", par, str, size, out, err, want, werr)
+		}
+	}
+}
@@ -1,13 +1,22 @@
 package refloat
 
-import "errors"
+import (
+	"errors"
+	"strconv"
+)
 
 type (
 	// A NumError records a failed conversion.
 	NumError struct {
-		Func string // the failing function (ParseBool, ParseInt, ParseUint, ParseFloat, ParseComplex)
+		Func string // the failing function (ParseFloat, ParseFloat16, ParseFloat128, ParseDecimal, ParseRat, ParseJSONNumber, ...)
 		Num  string // the input
 		Err  error  // the reason the conversion failed (e.g. ErrRange, ErrSyntax, etc.)
+
+		// for syntax errors, Offset is the byte offset in Num of the
+		// offending character, and Reason tells what is wrong there,
+		// such as ErrExponent. errors.Is matches both Err and Reason.
+		Offset int
+		Reason error
 	}
 )
 
@@ -16,24 +25,69 @@ var (
 	ErrRange = errors.New("value out of range")
 	// ErrSyntax indicates that a value does not have the right syntax for the target type.
 	ErrSyntax = errors.New("invalid syntax")
+	// ErrInexact indicates that a value had to be rounded, see Parser.Exact.
+	ErrInexact = errors.New("value is not exact")
+	// ErrUnderflow indicates that a non-zero value became subnormal or zero, see Parser.Underflow.
+	ErrUnderflow = errors.New("value underflows")
+)
+
+// the reasons of syntax errors, see NumError.Reason.
+var (
+	// ErrMantissa indicates that there are no digits where a number is expected, such as "", "-" or ".e1".
+	ErrMantissa = errors.New("missing mantissa digits")
+	// ErrExponent indicates that an exponent has no digits, such as "1e" or "0x1p+".
+	ErrExponent = errors.New("missing exponent digits")
+	// ErrHexExponent indicates that a hexadecimal mantissa has no 'p' exponent, such as "0x1.8".
+	ErrHexExponent = errors.New("missing 'p' exponent of hexadecimal mantissa")
+	// ErrSeparator indicates that '_' (or the group separator of a Locale) is not between digits, such as "1__0".
+	ErrSeparator = errors.New("misplaced digit separator")
+	// ErrSignedNaN indicates that NaN has a sign, such as "-NaN".
+	ErrSignedNaN = errors.New("signed NaN")
+	// ErrTrailing indicates that a number is followed by other characters, such as "1.5x".
+	ErrTrailing = errors.New("trailing characters")
+	// ErrNotAllowed indicates syntax that a Parser was told to reject, such as "+1" with NoLeadingPlus.
+	ErrNotAllowed = errors.New("not allowed by the parser")
+	// ErrPayload indicates that the payload of a NaN is not a number, such as "nan(x)".
+	ErrPayload = errors.New("invalid NaN payload")
+	// ErrImaginary indicates that the imaginary part of a complex number has no 'i', such as "1+2".
+	ErrImaginary = errors.New("missing imaginary unit")
 )
 
 func (err *NumError) Error() string {
-	return "refloat." + err.Func + ": " + "parsing " + quoteASCII(err.Num) + ": " + err.Err.Error()
+	str := "refloat." + err.Func + ": " + "parsing " + quoteASCII(err.Num) + ": " + err.Err.Error()
+	if err.Reason != nil {
+		str += ": " + err.Reason.Error() + " at offset " + strconv.Itoa(err.Offset)
+	}
+	return str
 }
 
 func (err *NumError) Unwrap() error {
 	return err.Err
 }
 
-func errorSyntax(fnc, num string) *NumError {
-	return &NumError{Func: fnc, Num: string([]byte(num)), Err: ErrSyntax}
+// Is reports whether target is the Reason of err,
+// so that errors.Is(err, ErrExponent) works.
+func (err *NumError) Is(target error) bool {
+	return err.Reason != nil && err.Reason == target
 }
 
-func errorRange(fnc, num string) *NumError {
+func errorSyntax[T text](fnc string, num T, offset int, reason error) *NumError {
+	return &NumError{Func: fnc, Num: string([]byte(num)), Err: ErrSyntax, Offset: offset, Reason: reason}
+}
+
+func errorRange[T text](fnc string, num T) *NumError {
 	return &NumError{Func: fnc, Num: string([]byte(num)), Err: ErrRange}
 }
 
+// errorOf makes a NumError from err, which is ErrRange or such.
+// it returns nil if err is nil.
+func errorOf[T text](fnc string, num T, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &NumError{Func: fnc, Num: string([]byte(num)), Err: err}
+}
+
 func quoteASCII(str string) string {
 	// TODO: implement proper Quote().
 	const hex = "0123456789abcdef"
@@ -0,0 +1,98 @@
+package refloat_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+type reasonTest struct {
+	par    Parser
+	inp    string
+	offset int
+	reason error
+}
+
+var reasontests = []reasonTest{
+	{Parser{}, "", 0, ErrMantissa},
+	{Parser{}, "-", 1, ErrMantissa},
+	{Parser{}, ".", 1, ErrMantissa},
+	{Parser{}, "+.e1", 2, ErrMantissa},
+	{Parser{}, "in", 0, ErrMantissa},
+	{Parser{}, "-nax", 1, ErrMantissa},
+	{Parser{}, "0x", 2, ErrMantissa},
+	{Parser{}, "0x.p1", 3, ErrMantissa},
+	{Parser{}, "1e", 2, ErrExponent},
+	{Parser{}, "1.2e+", 5, ErrExponent},
+	{Parser{}, "1.2ex", 4, ErrExponent},
+	{Parser{}, "0x1p", 4, ErrExponent},
+	{Parser{}, "0x1p-x", 5, ErrExponent},
+	{Parser{}, "0x1.8", 5, ErrHexExponent},
+	{Parser{}, "0x1.8e3", 7, ErrHexExponent},
+	{Parser{}, "_1", 0, ErrSeparator},
+	{Parser{}, "1_", 1, ErrSeparator},
+	{Parser{}, "1__0", 1, ErrSeparator},
+	{Parser{}, "1_.5", 1, ErrSeparator},
+	{Parser{}, "1e_5", 2, ErrSeparator},
+	{Parser{}, "0x_1_p_1", 4, ErrSeparator},
+	{Parser{Locale: Locale{Point: ',', Group: '.'}}, "1.23,5", 1, ErrSeparator},
+	{Parser{Locale: Locale{Point: ',', Group: '.'}}, "1234.567,5", 4, ErrSeparator},
+	{Parser{Locale: Locale{Point: ',', Group: '.'}}, "1,5.0", 3, ErrSeparator},
+	{Parser{}, "-NaN", 0, ErrSignedNaN},
+	{Parser{}, "+nan", 0, ErrSignedNaN},
+	{Parser{}, "1.5x", 3, ErrTrailing},
+	{Parser{}, "Infx", 3, ErrTrailing},
+	{Parser{}, "1e400 ", 5, ErrTrailing},
+	{Parser{NoUnderscores: true}, "1_000", 1, ErrTrailing},
+	{Parser{NoLeadingPlus: true}, "+1", 0, ErrNotAllowed},
+	{Parser{NoLeadingPoint: true}, "-.5", 1, ErrNotAllowed},
+	{Parser{NoTrailingPoint: true}, "5.e1", 1, ErrNotAllowed},
+	{Parser{NoLeadingZeros: true}, "-01", 1, ErrNotAllowed},
+}
+
+func TestNumErrorReason(t *testing.T) {
+	for _, test := range reasontests {
+		for _, size := range []int{32, 64} {
+			_, err := test.par.ParseFloat(test.inp, size)
+			var num *NumError
+			if !errors.As(err, &num) || !errors.Is(err, ErrSyntax) || !errors.Is(err, test.reason) || num.Offset != test.offset {
+				t.Errorf("%+v.ParseFloat(%q, %d) = %#v; want offset %d, reason %v", test.par, test.inp, size, err, test.offset, test.reason)
+			}
+		}
+	}
+}
+
+func TestNumErrorReasonComplex(t *testing.T) {
+	for _, test := range []struct {
+		inp    string
+		offset int
+		reason error
+	}{
+		{"(1e+i)", 4, ErrExponent},
+		{"1+2", 3, ErrImaginary},
+		{"1+2ix", 4, ErrTrailing},
+		{"1ix", 2, ErrTrailing},
+		{"1x", 1, ErrTrailing},
+		{"(1+_2i)", 3, ErrSeparator},
+	} {
+		_, err := ParseComplex(test.inp, 128)
+		var num *NumError
+		if !errors.As(err, &num) || !errors.Is(err, ErrSyntax) || !errors.Is(err, test.reason) || num.Offset != test.offset {
+			t.Errorf("ParseComplex(%q, 128) = %#v; want offset %d, reason %v", test.inp, err, test.offset, test.reason)
+		}
+	}
+}
+
+func TestNumErrorString(t *testing.T) {
+	_, err := ParseFloat("1.2e", 64)
+	want := `refloat.ParseFloat: parsing "1.2e": invalid syntax: missing exponent digits at offset 4`
+	if err == nil || err.Error() != want {
+		t.Errorf("ParseFloat(%q, 64) = %v; want %s", "1.2e", err, want)
+	}
+	_, err = ParseFloat("1e400", 64)
+	want = `refloat.ParseFloat: parsing "1e400": value out of range`
+	if err == nil || err.Error() != want {
+		t.Errorf("ParseFloat(%q, 64) = %v; want %s", "1e400", err, want)
+	}
+}
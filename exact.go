@@ -0,0 +1,17 @@
+package refloat
+
+// exactParser is std, but tells whether results are rounded.
+var exactParser = Parser{Exact: true}
+
+// ParseFloatExact is like ParseFloat, but also reports whether the result
+// is exactly the value of num, without any rounding. For example, "0.5" is
+// exact and "0.1" is not. Infs and NaNs are exact, and results that are
+// out of range are not.
+//
+// Unlike Parser.Exact, being inexact is not an error.
+func ParseFloatExact(num string, size int) (float64, bool, error) {
+	const fnc = "ParseFloatExact"
+	f64, read, inexact, err := readFloat(num, size, &exactParser)
+	f64, err = whole(num, f64, read, err, fnc)
+	return f64, err == nil && !inexact, err
+}
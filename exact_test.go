@@ -0,0 +1,105 @@
+package refloat_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+type exactTest struct {
+	inp   string
+	size  int
+	out   float64
+	exact bool
+	err   error
+}
+
+var exacttests = []exactTest{
+	{"0", 64, 0, true, nil},
+	{"-0", 64, math.Copysign(0, -1), true, nil},
+	{"1", 64, 1, true, nil},
+	{"0.5", 64, 0.5, true, nil},
+	{"0.1", 64, 0.1, false, nil},
+	{"-0.1", 32, float64(float32(-0.1)), false, nil},
+	{"0.3", 64, 0.3, false, nil},
+	{"1e22", 64, 1e22, true, nil},
+	{"1e23", 64, 1e23, false, nil},
+	{"16777216", 32, 16777216, true, nil},
+	{"16777217", 32, 16777216, false, nil},
+	{"16777217", 64, 16777217, true, nil},
+	{"9007199254740993", 64, 9007199254740992, false, nil},
+	{"1_000.25", 64, 1000.25, true, nil},
+	{"1.00000000000000011102230246251565404236316680908203125", 64, 1, false, nil},
+	{"1.0000000000000002220446049250313080847263336181640625", 64, 1.0000000000000002, true, nil},
+	{"4.9406564584124654e-324", 64, 5e-324, false, nil},
+	{"0.0009765625", 64, 0.0009765625, true, nil},
+	{"1e-400", 64, 0, false, nil},
+	{"0x1.8p1", 64, 3, true, nil},
+	{"0x1.00000000000008p0", 64, 1, false, nil},
+	{"0x1p-1074", 64, 5e-324, true, nil},
+	{"0x1p-1075", 64, 0, false, nil},
+	{"0x1.000001p0", 32, 1, false, nil},
+	{"Inf", 64, math.Inf(1), true, nil},
+	{"NaN", 64, math.NaN(), true, nil},
+	{"1e400", 64, math.Inf(1), false, ErrRange},
+	{"0.1x", 64, 0, false, ErrSyntax},
+}
+
+func TestParseFloatExact(t *testing.T) {
+	for _, test := range exacttests {
+		out, exact, err := ParseFloatExact(test.inp, test.size)
+		if !isFloat64(out, test.out) || exact != test.exact || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("ParseFloatExact(%q, %d) = %v, %v, %v want %v, %v, %v",
+				test.inp, test.size, out, exact, err, test.out, test.exact, test.err)
+		}
+	}
+	// every float is exact when all of its digits are there.
+	for _, f64 := range []float64{5e-324, 2.2250738585072014e-308, 0.1, 1e23, math.MaxFloat64} {
+		inp := strconv.FormatFloat(f64, 'e', 800, 64)
+		out, exact, err := ParseFloatExact(inp, 64)
+		if out != f64 || !exact || err != nil {
+			t.Errorf("ParseFloatExact(%q, 64) = %v, %v, %v want %v, true, <nil>", inp, out, exact, err, f64)
+		}
+	}
+}
+
+// results of Exact must be the same as without it, and
+// ErrInexact must be reported only for rounded ones.
+func TestParserExact(t *testing.T) {
+	for _, inp := range roundingInputs() {
+		rat, ok := new(big.Rat).SetString(inp)
+		if !ok {
+			t.Fatalf("bad test input %q", inp)
+		}
+		for _, mode := range roundingModes {
+			par := Parser{Rounding: mode, Exact: true}
+			for _, size := range []int{32, 64} {
+				want, over := roundRat(rat, size, mode)
+				exact := !over && new(big.Rat).SetFloat64(want).Cmp(rat) == 0
+				out, err := par.ParseFloat(inp, size)
+				if math.Float64bits(out) != math.Float64bits(want) || over != errors.Is(err, ErrRange) ||
+					!over && exact == errors.Is(err, ErrInexact) {
+					t.Errorf("Parser{Rounding: %v, Exact: true}.ParseFloat(%q, %d) = %v, %v want %v, exact: %v",
+						mode, inp, size, out, err, want, exact)
+				}
+			}
+		}
+	}
+}
+
+func TestParseFloatExactAllocs(t *testing.T) {
+	for _, inp := range []string{"0.5", "0.1", "-3.14159", "123e20", "0x1.00000000000008p0"} {
+		for _, size := range []int{32, 64} {
+			allocs := testing.AllocsPerRun(100, func() {
+				ParseFloatExact(inp, size)
+			})
+			if allocs != 0 {
+				t.Errorf("ParseFloatExact(%q, %d) allocated %v times, want 0", inp, size, allocs)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package refloat
+
+// A Float128 is the bits of an IEEE-754 binary128 (quadruple precision)
+// number. Hi has the sign, the exponent and the upper 48 bits of the mantissa.
+type Float128 struct {
+	Hi, Lo uint64
+}
+
+var (
+	binary128 = format{width: 128, prec: 112, bias: 16383}
+)
+
+// ParseFloat128 converts num to an IEEE-754 binary128 (quadruple precision)
+// number. num has the same syntax as for ParseFloat, and the result is
+// correctly rounded to nearest even.
+//
+// The errors are the same as ParseFloat, for the range of binary128.
+func ParseFloat128(num string) (Float128, error) {
+	return std.ParseFloat128(num)
+}
+
+// ParseFloat128 is like the package-level ParseFloat128, but with the syntax of p.
+func (p *Parser) ParseFloat128(num string) (Float128, error) {
+	const fnc = "ParseFloat128"
+	hi, lo, err := parseWide(num, p, binary128, fnc)
+	return Float128{Hi: hi, Lo: lo}, err
+}
+
+// FormatFloat128 returns the shortest decimal that ParseFloat128 reads
+// back as f, in the format of strconv.FormatFloat(f, 'g', -1, 64).
+func FormatFloat128(f Float128) string {
+	return formatWide(f.Hi, f.Lo, binary128)
+}
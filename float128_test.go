@@ -0,0 +1,220 @@
+package refloat_test
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+var float128tests = []struct {
+	inp string
+	out Float128
+	err error
+}{
+	{"1", Float128{0x3fff000000000000, 0}, nil},
+	{"-2", Float128{0xc000000000000000, 0}, nil},
+	{"0.1", Float128{0x3ffb999999999999, 0x999999999999999a}, nil},
+	{"3.14159265358979323846264338327950288", Float128{0x4000921fb54442d1, 0x8469898cc51701b8}, nil},
+	{"1e27", Float128{0x40589d971e4fe840, 0x1e74000000000000}, nil},
+	{"1e-27", Float128{0x3fa53ce9a36f23c0, 0xfc90eebd44c99eaa}, nil},
+	{"123456789012345678901234567890", Float128{0x405f8ee90ff6c373, 0xe0ee4e3f0ad20000}, nil},
+	{"1.18973149535723176508575932662800702e4932", Float128{0x7ffeffffffffffff, 0xffffffffffffffff}, nil},
+	{"1.2e4932", Float128{0x7fff000000000000, 0}, ErrRange},
+	{"3.36210314311209350626267781732175260e-4932", Float128{0x0001000000000000, 0}, nil},
+	{"6.5e-4966", Float128{0, 1}, nil},
+	{"3.2e-4966", Float128{0, 0}, nil},
+	{"3.3e-4966", Float128{0, 1}, nil},
+	{"-0", Float128{0x8000000000000000, 0}, nil},
+	{"0x1.8p1", Float128{0x4000800000000000, 0}, nil},
+	{"0x1.0000000000000000000000000000_8p0", Float128{0x3fff000000000000, 0}, nil},
+	{"0x1.0000000000000000000000000000_81p0", Float128{0x3fff000000000000, 1}, nil},
+	// 2^113+1 is a tie, 2^113+3 is the next one.
+	{"10384593717069655257060992658440193", Float128{0x4070000000000000, 0}, nil},
+	{"10384593717069655257060992658440195", Float128{0x4070000000000000, 2}, nil},
+	{"10384593717069655257060992658440193.0000000000000000000000001", Float128{0x4070000000000000, 1}, nil},
+	{"inf", Float128{0x7fff000000000000, 0}, nil},
+	{"-Infinity", Float128{0xffff000000000000, 0}, nil},
+	{"nan", Float128{0x7fff800000000000, 0}, nil},
+	{"1e", Float128{}, ErrSyntax},
+	{"1x", Float128{}, ErrSyntax},
+}
+
+func TestParseFloat128(t *testing.T) {
+	for _, test := range float128tests {
+		out, err := ParseFloat128(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("ParseFloat128(%q) = %#x, %v want %#x, %v", test.inp, out, err, test.out, test.err)
+		}
+	}
+	_, err := ParseFloat128("1.5e")
+	var num *NumError
+	if !errors.As(err, &num) || num.Func != "ParseFloat128" || !errors.Is(err, ErrExponent) {
+		t.Errorf("ParseFloat128(%q) = _, %#v; want a *NumError for ParseFloat128", "1.5e", err)
+	}
+}
+
+func TestParseFloat128Parser(t *testing.T) {
+	for _, test := range []struct {
+		par Parser
+		inp string
+		out Float128
+		err error
+	}{
+		{Parser{Exact: true}, "0.5", Float128{0x3ffe000000000000, 0}, nil},
+		{Parser{Exact: true}, "0.1", Float128{0x3ffb999999999999, 0x999999999999999a}, ErrInexact},
+		{Parser{Rounding: big.ToZero}, "0.1", Float128{0x3ffb999999999999, 0x9999999999999999}, nil},
+		{Parser{Rounding: big.ToZero}, "1e5000", Float128{0x7ffeffffffffffff, 0xffffffffffffffff}, ErrRange},
+		{Parser{Underflow: true}, "1e-4940", Float128{0x00000000000cc64f, 0x1cc4376f7da08f39}, ErrUnderflow},
+		{Parser{NaNPayloads: true}, "-nan(0x123456789)", Float128{0xffff800000000000, 0x123456789}, nil},
+		{Parser{NaNPayloads: true}, "snan", Float128{0x7fff000000000000, 1}, nil},
+		{Parser{Specials: AltSpecials()}, "-1.#INF", Float128{0xffff000000000000, 0}, nil},
+	} {
+		out, err := test.par.ParseFloat128(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("%+v.ParseFloat128(%q) = %#x, %v want %#x, %v", test.par, test.inp, out, err, test.out, test.err)
+		}
+	}
+}
+
+func TestFormatFloat128(t *testing.T) {
+	for _, test := range []struct {
+		out string
+		inp Float128
+	}{
+		{"1", Float128{0x3fff000000000000, 0}},
+		{"0.1", Float128{0x3ffb999999999999, 0x999999999999999a}},
+		{"3.1415926535897932384626433832795028", Float128{0x4000921fb54442d1, 0x8469898cc51701b8}},
+		{"1.189731495357231765085759326628007e+4932", Float128{0x7ffeffffffffffff, 0xffffffffffffffff}},
+		{"6e-4966", Float128{0, 1}},
+		{"100000", Float128{0x400f86a000000000, 0}},
+		{"1e+06", Float128{0x4012e84800000000, 0}},
+		{"0.0001", Float128{0x3ff1a36e2eb1c432, 0xca57a786c226809d}},
+		{"-0", Float128{0x8000000000000000, 0}},
+		{"-Inf", Float128{0xffff000000000000, 0}},
+		{"NaN", Float128{0x7fff800000000000, 0}},
+	} {
+		if out := FormatFloat128(test.inp); out != test.out {
+			t.Errorf("FormatFloat128(%#x) = %q want %q", test.inp, out, test.out)
+		}
+	}
+}
+
+func TestFloat128RoundTrip(t *testing.T) {
+	try := 1000
+	if testing.Short() {
+		try = 50
+	}
+	for ; try > 0; try-- {
+		inp := Float128{rand.Uint64(), rand.Uint64()}
+		if inp.Hi>>48&0x7fff == 0x7fff {
+			continue
+		}
+		str := FormatFloat128(inp)
+		if out, err := ParseFloat128(str); out != inp || err != nil {
+			t.Fatalf("ParseFloat128(FormatFloat128(%#x)) = ParseFloat128(%q) = %#x, %v", inp, str, out, err)
+		}
+	}
+}
+
+// the normal range is compared with big.Float, which rounds correctly.
+func TestFloat128Rounding(t *testing.T) {
+	try := 2000
+	if testing.Short() {
+		try = 200
+	}
+	for ; try > 0; try-- {
+		// random digits, and midpoints of neighbors.
+		mant := new(big.Int).Rand(rand.New(rand.NewSource(int64(try))), new(big.Int).Lsh(big.NewInt(1), uint(rand.Intn(160)+1)))
+		exp := rand.Intn(600) - 300
+		if try%2 == 0 {
+			mant.SetBit(mant, 114, 1)
+			mant.Rsh(mant, uint(mant.BitLen()-114))
+			mant.SetBit(mant, 0, 1)
+		}
+		rat := new(big.Rat).SetInt(mant)
+		if exp > 0 {
+			rat.Mul(rat, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(exp))))
+		} else {
+			rat.Quo(rat, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(-exp))))
+		}
+		if rat.Sign() == 0 {
+			continue
+		}
+		str := exactDecimal(rat)
+		want := new(big.Float).SetPrec(113).SetRat(rat)
+		out, err := ParseFloat128(str)
+		if err != nil || float128Big(out).Cmp(want) != 0 {
+			t.Fatalf("ParseFloat128(%q) = %#x, %v want %v", str, out, err, want.Text('p', 0))
+		}
+	}
+}
+
+// short decimals with large exponents go through the fast path.
+func TestFloat128Short(t *testing.T) {
+	try := 500
+	if testing.Short() {
+		try = 50
+	}
+	for ; try > 0; try-- {
+		mant := rand.Int63n(1e18) + 1
+		exp := rand.Intn(9800) - 4900
+		mode := roundingModes[try%len(roundingModes)]
+		str := strconv.FormatInt(mant, 10) + "e" + strconv.Itoa(exp)
+		rat := new(big.Rat).SetInt64(mant)
+		pow := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(exp, -exp))), nil))
+		if exp > 0 {
+			rat.Mul(rat, pow)
+		} else {
+			rat.Quo(rat, pow)
+		}
+		want := new(big.Float).SetPrec(113).SetMode(mode).SetRat(rat)
+		par := Parser{Rounding: mode}
+		out, err := par.ParseFloat128(str)
+		if err != nil || float128Big(out).Cmp(want) != 0 {
+			t.Fatalf("Parser{Rounding: %v}.ParseFloat128(%q) = %#x, %v want %v", mode, str, out, err, want.Text('p', 0))
+		}
+	}
+}
+
+func TestParseFloat128Allocs(t *testing.T) {
+	for _, inp := range []string{"0.1e-30", "-3.14159e4000", "1.2345678901234567e-4000", "5e-4950"} {
+		allocs := testing.AllocsPerRun(100, func() {
+			ParseFloat128(inp)
+			ParseFloat80(inp)
+		})
+		if allocs != 0 {
+			t.Errorf("ParseFloat128 and ParseFloat80(%q) allocated %v times, want 0", inp, allocs)
+		}
+	}
+}
+
+// exactDecimal returns rat, which is a dyadic rational, as a decimal.
+func exactDecimal(rat *big.Rat) string {
+	num, den := new(big.Int).Set(rat.Num()), rat.Denom()
+	exp := den.BitLen() - 1
+	num.Mul(num, new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(exp)), nil))
+	return num.String() + "e-" + big.NewInt(int64(exp)).String()
+}
+
+// float128Big returns the value of a finite x.
+func float128Big(x Float128) *big.Float {
+	mant := new(big.Int).SetUint64(x.Hi & (1<<48 - 1))
+	mant.Lsh(mant, 64)
+	mant.Or(mant, new(big.Int).SetUint64(x.Lo))
+	exp := int(x.Hi >> 48 & 0x7fff)
+	if exp == 0 {
+		exp = 1
+	} else {
+		mant.SetBit(mant, 112, 1)
+	}
+	val := new(big.Float).SetInt(mant)
+	val.SetMantExp(val, exp-16383-112)
+	if x.Hi>>63 != 0 {
+		val.Neg(val)
+	}
+	return val
+}
@@ -0,0 +1,109 @@
+package refloat
+
+import (
+	"math"
+	"strconv"
+)
+
+// ParseFloat16 converts num to an IEEE-754 binary16 (half precision) number
+// and returns its bits. num has the same syntax as for ParseFloat, and the
+// result is correctly rounded: it's not rounded to float64 first.
+//
+// The errors are the same as ParseFloat, for the range of binary16.
+func ParseFloat16(num string) (uint16, error) {
+	return std.ParseFloat16(num)
+}
+
+// ParseBFloat16 is like ParseFloat16, but for bfloat16,
+// which has the exponent of float32 and 7 bits of mantissa.
+func ParseBFloat16(num string) (uint16, error) {
+	return std.ParseBFloat16(num)
+}
+
+// ParseFloat16 is like the package-level ParseFloat16, but with the syntax of p.
+func (p *Parser) ParseFloat16(num string) (uint16, error) {
+	const fnc = "ParseFloat16"
+	u64, err := parseNarrow(num, p, binary16, fnc)
+	return uint16(u64), err
+}
+
+// ParseBFloat16 is like the package-level ParseBFloat16, but with the syntax of p.
+func (p *Parser) ParseBFloat16(num string) (uint16, error) {
+	const fnc = "ParseBFloat16"
+	u64, err := parseNarrow(num, p, bfloat16, fnc)
+	return uint16(u64), err
+}
+
+// FormatFloat16 returns the shortest decimal that ParseFloat16 reads
+// back as bits, in the format of strconv.FormatFloat(f, 'g', -1, 64).
+func FormatFloat16(bits uint16) string {
+	return formatShortest(uint64(bits), binary16)
+}
+
+// FormatBFloat16 is like FormatFloat16, but for bfloat16.
+func FormatBFloat16(bits uint16) string {
+	return formatShortest(uint64(bits), bfloat16)
+}
+
+// parseNarrow parses num to f, which is narrower than float64.
+func parseNarrow[T text](num T, p *Parser, f format, fnc string) (uint64, error) {
+	f64, err := parseAll(num, fnc, p, toOdd)
+	if err != nil && err.(*NumError).Err == ErrSyntax {
+		return 0, err
+	}
+	u64, nerr := f.narrow(math.Float64bits(f64), p)
+	if f64 != f64 && err != nil {
+		// the payload didn't fit even in float64.
+		nerr = ErrRange
+	}
+	return u64, errorOf(fnc, num, nerr)
+}
+
+// formatShortest returns the shortest decimal that is read back as the bits of f.
+func formatShortest(u64 uint64, f format) string {
+	f64 := f.widen(u64)
+	if f64 == 0 || math.IsInf(f64, 0) || math.IsNaN(f64) {
+		return strconv.FormatFloat(f64, 'g', -1, 64)
+	}
+	for digs := 1; ; digs++ {
+		str := strconv.FormatFloat(f64, 'e', digs-1, 64)
+		near, _ := std.ParseFloat64(str)
+		if back, err := parseNarrow(str, &std, f, ""); back == u64 && err == nil {
+			return strconv.FormatFloat(near, 'g', -1, 64)
+		}
+		if math.Abs(near) > math.Abs(f64) {
+			continue
+		}
+		// the nearest decimal with digs digits is too far, but the next one
+		// on the other side may not be, if the gap there is wider.
+		str = up(str)
+		near, _ = std.ParseFloat64(str)
+		if back, err := parseNarrow(str, &std, f, ""); back == u64 && err == nil {
+			return strconv.FormatFloat(near, 'g', -1, 64)
+		}
+	}
+}
+
+// up returns the decimal next to num, which is in the 'e' format,
+// with the same number of digits but away from zero.
+func up(num string) string {
+	exp := len(num)
+	for num[exp-1] != 'e' {
+		exp--
+	}
+	exp--
+	digs := []byte(num[:exp])
+	idx := len(digs) - 1
+	for ; idx >= 0 && (digs[idx] == '9' || digs[idx] == '.'); idx-- {
+		if digs[idx] == '9' {
+			digs[idx] = '0'
+		}
+	}
+	if idx < 0 || digs[idx] == '-' {
+		// "9.9e1" becomes "10.0e1", which is still a valid number.
+		digs = append(digs[:idx+1], append([]byte{'1'}, digs[idx+1:]...)...)
+	} else {
+		digs[idx]++
+	}
+	return string(digs) + num[exp:]
+}
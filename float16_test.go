@@ -0,0 +1,268 @@
+package refloat_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+type float16Test struct {
+	inp string
+	out uint16
+	err error
+}
+
+var float16tests = []float16Test{
+	{"1", 0x3c00, nil},
+	{"-2", 0xc000, nil},
+	{"0.1", 0x2e66, nil},
+	{"65504", 0x7bff, nil},
+	{"65519.99", 0x7bff, nil},
+	{"65520", 0x7c00, ErrRange},
+	{"-1e10", 0xfc00, ErrRange},
+	{"6e-8", 0x0001, nil},
+	{"2.98023223876953125e-08", 0x0000, nil},
+	{"2.98023223876953126e-08", 0x0001, nil},
+	{"1e-400", 0x0000, nil},
+	{"-0", 0x8000, nil},
+	{"0x1.ffcp15", 0x7bff, nil},
+	{"0x1.002p0", 0x3c00, nil},
+	{"0x1.0021p0", 0x3c01, nil},
+	// float64 would round these to the tie, and then to even.
+	{"1.00048828125000000001", 0x3c01, nil},
+	{"1.00146484374999999999", 0x3c01, nil},
+	{"inf", 0x7c00, nil},
+	{"-Infinity", 0xfc00, nil},
+	{"nan", 0x7e00, nil},
+	{"1x", 0, ErrSyntax},
+	{"", 0, ErrSyntax},
+}
+
+var bfloat16tests = []float16Test{
+	{"1", 0x3f80, nil},
+	{"3.14", 0x4049, nil},
+	{"-0.1", 0xbdcd, nil},
+	{"3.3895314e38", 0x7f7f, nil},
+	{"3.3961775e38", 0x7f7f, nil},
+	{"3.3961776e38", 0x7f80, ErrRange},
+	{"1e-40", 0x0001, nil},
+	{"9.2e-41", 0x0001, nil},
+	{"4.5e-41", 0x0000, nil},
+	{"4.6e-41", 0x0001, nil},
+	{"0x1.01p0", 0x3f80, nil},
+	{"0x1.03p0", 0x3f82, nil},
+	{"0x1.0100000000000000001p0", 0x3f81, nil},
+	{"1.00390625000000000001", 0x3f81, nil},
+	{"-inf", 0xff80, nil},
+	{"nan", 0x7fc0, nil},
+	{"1_0", 0x4120, nil},
+	{"1__0", 0, ErrSyntax},
+}
+
+func TestParseFloat16(t *testing.T) {
+	for _, test := range float16tests {
+		out, err := ParseFloat16(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("ParseFloat16(%q) = %#04x, %v want %#04x, %v", test.inp, out, err, test.out, test.err)
+		}
+	}
+	for _, test := range bfloat16tests {
+		out, err := ParseBFloat16(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("ParseBFloat16(%q) = %#04x, %v want %#04x, %v", test.inp, out, err, test.out, test.err)
+		}
+	}
+}
+
+func TestParseFloat16Error(t *testing.T) {
+	_, err := ParseFloat16("1.5e")
+	var num *NumError
+	if !errors.As(err, &num) || num.Func != "ParseFloat16" || num.Num != "1.5e" || !errors.Is(err, ErrExponent) {
+		t.Errorf("ParseFloat16(%q) = _, %#v; want a *NumError for ParseFloat16", "1.5e", err)
+	}
+}
+
+func TestParseFloat16Parser(t *testing.T) {
+	for _, test := range []struct {
+		par Parser
+		inp string
+		out uint16
+		err error
+	}{
+		{Parser{Exact: true}, "0.5", 0x3800, nil},
+		{Parser{Exact: true}, "0.1", 0x2e66, ErrInexact},
+		{Parser{Exact: true}, "2049", 0x6800, ErrInexact},
+		{Parser{Underflow: true}, "6e-8", 0x0001, ErrUnderflow},
+		{Parser{Underflow: true}, "6.1035156e-05", 0x0400, nil},
+		{Parser{FlushToZero: true}, "-6e-8", 0x8000, nil},
+		{Parser{Overflow: OverflowSaturate}, "1e6", 0x7bff, ErrRange},
+		{Parser{Overflow: OverflowSaturateSilent}, "-1e400", 0xfbff, nil},
+		{Parser{Rounding: big.ToZero}, "1e400", 0x7bff, ErrRange},
+		{Parser{NaNPayloads: true}, "-nan(5)", 0xfe05, nil},
+		{Parser{NaNPayloads: true}, "snan", 0x7c01, nil},
+		{Parser{NaNPayloads: true}, "nan(0x200)", 0x7e00, ErrRange},
+		{Parser{Specials: AltSpecials()}, "-1.#IND", 0xfe00, nil},
+	} {
+		out, err := test.par.ParseFloat16(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("%+v.ParseFloat16(%q) = %#04x, %v want %#04x, %v", test.par, test.inp, out, err, test.out, test.err)
+		}
+	}
+}
+
+// minifloat is a format narrower than float32, and its functions.
+// format is nil if there is none.
+type minifloat struct {
+	name   string
+	parse  func(*Parser, string) (uint16, error)
+	format func(uint16) string
+	prec   int
+	bias   int
+	top    uint16 // the first bits that are not finite.
+	sign   uint16
+}
+
+var float16s = []minifloat{
+	{"Float16", (*Parser).ParseFloat16, FormatFloat16, 10, 15, 0x7c00, 0x8000},
+	{"BFloat16", (*Parser).ParseBFloat16, FormatBFloat16, 7, 127, 0x7f80, 0x8000},
+}
+
+func TestFormatFloat16(t *testing.T) {
+	for _, test := range []struct {
+		out string
+		fnc func(uint16) string
+		inp uint16
+	}{
+		{"1", FormatFloat16, 0x3c00},
+		{"0.3333", FormatFloat16, 0x3555},
+		{"65500", FormatFloat16, 0x7bff},
+		{"6e-08", FormatFloat16, 0x0001},
+		{"-0", FormatFloat16, 0x8000},
+		{"+Inf", FormatFloat16, 0x7c00},
+		{"NaN", FormatFloat16, 0x7e00},
+		{"1", FormatBFloat16, 0x3f80},
+		{"3.14", FormatBFloat16, 0x4049},
+		{"-0.1", FormatBFloat16, 0xbdcd},
+		{"3.39e+38", FormatBFloat16, 0x7f7f},
+		{"9e-41", FormatBFloat16, 0x0001},
+		{"-Inf", FormatBFloat16, 0xff80},
+	} {
+		if out := test.fnc(test.inp); out != test.out {
+			t.Errorf("Format(%#04x) = %q want %q", test.inp, out, test.out)
+		}
+	}
+}
+
+// every value has to come back from its shortest form.
+func TestFloat16RoundTrip(t *testing.T) {
+	for _, typ := range float16s {
+		for bit := 0; bit < 1<<16; bit++ {
+			str := typ.format(uint16(bit))
+			out, err := typ.parse(&Parser{}, str)
+			nan := bit&0x7fff > int(typ.top)
+			if nan && out&0x7fff <= typ.top || !nan && out != uint16(bit) || err != nil {
+				t.Fatalf("Parse%s(Format%s(%#04x)) = Parse%s(%q) = %#04x, %v", typ.name, typ.name, bit, typ.name, str, out, err)
+			}
+			// no shorter decimal comes back.
+			if nan || bit&0x7fff >= int(typ.top) || bit&0x7fff == 0 {
+				continue
+			}
+			f64, _ := strconv.ParseFloat(str, 64)
+			// the number of digits in the 'e' format without the point.
+			digs := strings.IndexByte(strings.Replace(strconv.FormatFloat(math.Abs(f64), 'e', -1, 64), ".", "", 1), 'e')
+			if digs > 1 {
+				short := strconv.FormatFloat(f64, 'e', digs-2, 64)
+				if out, _ := typ.parse(&Parser{}, short); out == uint16(bit) {
+					t.Fatalf("Format%s(%#04x) = %q, but %q is shorter", typ.name, bit, str, short)
+				}
+			}
+		}
+	}
+}
+
+func TestFloat16Rounding(t *testing.T) {
+	for _, typ := range float16s {
+		testMiniRounding(t, typ)
+	}
+}
+
+// testMiniRounding tests every finite value and every midpoint of
+// neighbors, in every rounding mode.
+func testMiniRounding(t *testing.T, typ minifloat) {
+	for bit := uint16(0); bit < typ.top; bit++ {
+		if testing.Short() && bit%16 != 0 {
+			continue
+		}
+		lo, hi := exactMini(typ, bit), exactMini(typ, bit+1)
+		mid := new(big.Rat).Add(lo, hi)
+		mid.Quo(mid, big.NewRat(2, 1))
+		for _, neg := range []uint16{0, typ.sign} {
+			sign := ""
+			if neg != 0 {
+				sign = "-"
+			}
+			// the denominator is 2^k, which needs k digits after the point.
+			exact := sign + lo.FloatString(lo.Denom().BitLen()-1)
+			inp := sign + mid.FloatString(mid.Denom().BitLen()-1)
+			for _, mode := range roundingModes {
+				out, err := typ.parse(&Parser{Rounding: mode}, exact)
+				if out != bit|neg || err != nil {
+					t.Fatalf("Parser{Rounding: %v}.Parse%s(%q) = %#04x, %v want %#04x, <nil>", mode, typ.name, exact, out, err, bit|neg)
+				}
+				want := bit
+				switch mode {
+				case big.ToNearestEven:
+					want += bit & 1
+				case big.ToNearestAway, big.AwayFromZero:
+					want++
+				case big.ToPositiveInf:
+					if neg == 0 {
+						want++
+					}
+				case big.ToNegativeInf:
+					if neg != 0 {
+						want++
+					}
+				}
+				out, err = typ.parse(&Parser{Rounding: mode}, inp)
+				if out != want|neg || (want == typ.top) != errors.Is(err, ErrRange) {
+					t.Fatalf("Parser{Rounding: %v}.Parse%s(%q) = %#04x, %v want %#04x", mode, typ.name, inp, out, err, want|neg)
+				}
+			}
+			// just above the midpoint.
+			above := inp + "1"
+			if !strings.Contains(inp, ".") {
+				above = inp + ".1"
+			}
+			out, err := typ.parse(&Parser{}, above)
+			if out != (bit+1)|neg || (bit+1 == typ.top) != errors.Is(err, ErrRange) {
+				t.Fatalf("Parse%s(%q) = %#04x, %v want %#04x", typ.name, above, out, err, (bit+1)|neg)
+			}
+		}
+	}
+}
+
+// exactMini returns the exact value of the bits, which are finite, or
+// the first ones that are not. those are where the next finite number
+// would be.
+func exactMini(typ minifloat, bit uint16) *big.Rat {
+	prec, bias := typ.prec, typ.bias
+	exp := int(bit >> prec)
+	mant := int64(bit & (1<<prec - 1))
+	if exp == 0 {
+		exp = 1
+	} else {
+		mant |= 1 << prec
+	}
+	rat := new(big.Rat).SetInt64(mant)
+	scale := new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(max(exp-bias-prec, bias+prec-exp))))
+	if exp-bias-prec >= 0 {
+		return rat.Mul(rat, scale)
+	}
+	return rat.Quo(rat, scale)
+}
@@ -17,7 +17,17 @@ const (
 	nan32 = 0x7f800001
 )
 
-func parseFloat32(num string) (float32, int, error) {
+func parseFloat32[T text](num T, p *Parser) (float32, int, error) {
+	const fnc = "ParseFloat"
+	f32, offset, inexact, err := read32(num, p)
+	if err == nil && inexact && p.Exact {
+		err = errorOf(fnc, num, ErrInexact)
+	}
+	return f32, offset, err
+}
+
+// read32 is parseFloat32 like read64.
+func read32[T text](num T, p *Parser) (float32, int, bool, error) {
 	const fnc = "ParseFloat"
 	var (
 		sign  int
@@ -26,8 +36,11 @@ func parseFloat32(num string) (float32, int, error) {
 	)
 	var offset int
 	if offset >= len(num) {
-		return 0, 0, errorSyntax(fnc, num)
+		return 0, 0, false, errorSyntax(fnc, num, offset, ErrMantissa)
 	} else if num[offset] == '+' {
+		if p.NoLeadingPlus {
+			return 0, 0, false, errorSyntax(fnc, num, offset, ErrNotAllowed)
+		}
 		offset++
 	} else if num[offset] == '-' {
 		offset++
@@ -35,42 +48,67 @@ func parseFloat32(num string) (float32, int, error) {
 	}
 
 	if offset >= len(num) {
-		return 0, 0, errorSyntax(fnc, num)
+		return 0, 0, false, errorSyntax(fnc, num, offset, ErrMantissa)
+	}
+
+	if len(p.Specials) != 0 {
+		if spec, read := special(num[offset:], p); read != 0 {
+			if spec.NaN {
+				return math.Float32frombits(0x7fc<<20 | uint32(sign)<<31), offset + read, false, nil
+			}
+			return math.Float32frombits(inf32 | uint32(sign)<<31), offset + read, false, nil
+		}
 	}
 
-	if num[offset]|0x20 == 'i' {
-		comm := common(num[offset+1:], "nfinity")
-		if comm == 7 {
-			return math.Float32frombits(inf32 | uint32(sign)<<31), offset + 8, nil
+	if num[offset]|0x20 == 'i' && !p.NoSpecials {
+		comm := common(num[offset:], "Infinity", p.CaseSensitive)
+		if comm == 8 {
+			return math.Float32frombits(inf32 | uint32(sign)<<31), offset + 8, false, nil
 		}
-		if comm == 2 {
-			return math.Float32frombits(inf32 | uint32(sign)<<31), offset + 3, nil
+		if comm >= 3 {
+			return math.Float32frombits(inf32 | uint32(sign)<<31), offset + 3, false, nil
 		}
-		return 0, 0, errorSyntax(fnc, num)
+		return 0, 0, false, errorSyntax(fnc, num, offset, ErrMantissa)
+	}
+
+	if p.NaNPayloads && !p.NoSpecials && (num[offset]|0x20 == 'n' || num[offset]|0x20 == 's') {
+		u64, offset, err := nanParseFloat(num, offset, sign, p, binary32)
+		return math.Float32frombits(uint32(u64)), offset, false, err
 	}
 
-	if num[offset]|0x20 == 'n' {
-		comm := common(num[offset+1:], "an")
-		if comm == 2 && offset == 0 {
-			return math.Float32frombits(nan32), offset + 3, nil
+	if num[offset]|0x20 == 'n' && !p.NoSpecials {
+		comm := common(num[offset:], "NaN", p.CaseSensitive)
+		if comm == 3 && offset == 0 {
+			return math.Float32frombits(nan32), offset + 3, false, nil
+		}
+		if comm == 3 {
+			return 0, 0, false, errorSyntax(fnc, num, 0, ErrSignedNaN)
 		}
-		return 0, 0, errorSyntax(fnc, num)
+		return 0, 0, false, errorSyntax(fnc, num, offset, ErrMantissa)
 	}
 
-	if offset+1 < len(num) && num[offset] == '0' && num[offset+1]|0x20 == 'x' {
-		u64, offset, err := hexParseFloat(num, 0)
-		return math.Float32frombits(uint32(u64)), offset, err
+	if offset+1 < len(num) && num[offset] == '0' && num[offset+1]|0x20 == 'x' && !p.NoHex {
+		u64, offset, inexact, err := hexParseFloat(num, p, binary32)
+		return math.Float32frombits(uint32(u64)), offset, inexact, err
 	}
 
 	const limit = 0x19999999
 	var point, digit, line bool
+	mark, sep, lines := p.notation()
+	if num[offset] == '0' && p.NoLeadingZeros && offset+1 < len(num) && (num[offset+1]-'0' <= '9'-'0' || num[offset+1] == sep) {
+		return 0, 0, false, errorSyntax(fnc, num, offset, ErrNotAllowed)
+	}
+	begin := offset
 	for ; offset < len(num); offset++ {
 		char := num[offset]
-		if char == '.' && !point {
+		if char == mark && !point {
+			if !digit && p.NoLeadingPoint {
+				return 0, 0, false, errorSyntax(fnc, num, offset, ErrNotAllowed)
+			}
 			point = true
 			continue
 		}
-		if char == '_' {
+		if char == sep && lines {
 			line = true
 			continue
 		}
@@ -90,8 +128,12 @@ func parseFloat32(num string) (float32, int, error) {
 	}
 
 	if !digit {
-		return 0, 0, errorSyntax(fnc, num)
+		return 0, 0, false, errorSyntax(fnc, num, offset, ErrMantissa)
 	}
+	if point && num[offset-1] == mark && p.NoTrailingPoint {
+		return 0, 0, false, errorSyntax(fnc, num, offset-1, ErrNotAllowed)
+	}
+	end := offset
 
 	if offset < len(num) && num[offset]|0x20 == 'e' {
 		const limit = 308 + 20 + 20
@@ -99,7 +141,7 @@ func parseFloat32(num string) (float32, int, error) {
 		var esign, edigit bool
 		offset++
 		if offset >= len(num) {
-			return 0, 0, errorSyntax(fnc, num)
+			return 0, 0, false, errorSyntax(fnc, num, offset, ErrExponent)
 		} else if num[offset] == '+' {
 			offset++
 		} else if offset < len(num) && num[offset] == '-' {
@@ -108,7 +150,7 @@ func parseFloat32(num string) (float32, int, error) {
 		}
 		for ; offset < len(num); offset++ {
 			char := num[offset]
-			if char == '_' {
+			if char == '_' && lines && sep == '_' {
 				line = true
 				continue
 			}
@@ -131,21 +173,25 @@ func parseFloat32(num string) (float32, int, error) {
 			exp10 += shift
 		}
 		if !edigit {
-			return 0, 0, errorSyntax(fnc, num)
+			return 0, 0, false, errorSyntax(fnc, num, offset, ErrExponent)
 		}
 	}
 
-	if line {
-		for idx := 0; idx < len(num); idx++ {
+	if line && p.Locale.Group != 0 {
+		if idx := grouped(num[begin:end], mark, sep, p.Locale.Indian); idx >= 0 {
+			return 0, 0, false, errorSyntax(fnc, num, begin+idx, ErrSeparator)
+		}
+	} else if line {
+		for idx := 0; idx < offset; idx++ {
 			if num[idx] != '_' {
 				continue
 			}
-			if idx == 0 || idx == len(num)-1 {
-				return 0, 0, errorSyntax(fnc, num)
+			if idx == 0 || idx == offset-1 {
+				return 0, 0, false, errorSyntax(fnc, num, idx, ErrSeparator)
 			}
 			lo, hi := num[idx-1], num[idx+1]
 			if lo-'0' > '9'-'0' || hi-'0' > '9'-'0' {
-				return 0, 0, errorSyntax(fnc, num)
+				return 0, 0, false, errorSyntax(fnc, num, idx, ErrSeparator)
 			}
 		}
 	}
@@ -158,15 +204,41 @@ func parseFloat32(num string) (float32, int, error) {
 		} else {
 			f32 /= pow10float32[abs]
 		}
-		if sign > 0 {
-			f32 = -f32
+		if p.nearest() {
+			if sign > 0 {
+				f32 = -f32
+			}
+			return f32, offset, false, nil
+		}
+		// see compose64 for the rounding in other modes.
+		var diff float64
+		if exp10 > 0 {
+			diff = math.FMA(float64(mant), float64(pow10float32[abs]), -float64(f32))
+		} else {
+			diff = math.FMA(-float64(f32), float64(pow10float32[abs]), float64(mant))
 		}
-		return f32, offset, nil
+		u32 := math.Float32bits(f32)
+		if diff == 0 {
+			return math.Float32frombits(u32 | uint32(sign)<<31), offset, false, nil
+		}
+		var up uint32
+		if diff < 0 {
+			up = 1
+		}
+		lo := u32 - up
+		ulp := float64(math.Float32frombits(lo+1)) - float64(math.Float32frombits(lo))
+		tie := exp10 > 0 && math.Abs(diff)*2 == ulp
+		var half uint64
+		if up != 0 || tie {
+			half = 1
+		}
+		u32 = lo + uint32(round(uint64(lo)<<1|half, !tie, sign, p.Rounding))
+		return math.Float32frombits(u32 | uint32(sign)<<31), offset, true, nil
 	}
 
 	if mant == 0 {
 		f32 := math.Float32frombits(uint32(sign) << 31)
-		return f32, offset, nil
+		return f32, offset, false, nil
 	}
 
 	exp := exp10
@@ -230,30 +302,45 @@ func parseFloat32(num string) (float32, int, error) {
 		exp = 0
 	}
 
-	hir := hip << (prec + 1)
-	lor := lop << (prec + 1)
-	hip = hip>>(31-prec) + hir>>31
-	lop = lop>>(31-prec) + lor>>31
+	var slow bool
+	if p.nearest() {
+		hir := hip << (prec + 1)
+		lor := lop << (prec + 1)
+		hip = hip>>(31-prec) + hir>>31
+		lop = lop>>(31-prec) + lor>>31
+		slow = lor <= 1<<31 && hir > 1<<31 || hip != lop
+	} else {
+		slow = lop>>(30-prec) != hip>>(30-prec) || lop<<(prec+2) == 0
+		lop = lop>>(31-prec) + uint32(round(uint64(lop>>(30-prec)), true, sign, p.Rounding))
+	}
+	if slow {
+		u64, inexact, err := bigParseFloat(num[:offset], p, binary32)
+		return math.Float32frombits(uint32(u64)), offset, inexact, errorOf(fnc, num, err)
+	}
 
 	if lop>>prec != 0 && exp == 0 {
 		exp++
 	}
 
 	carry := lop >> (prec + 1)
-	hip >>= carry
 	lop >>= carry
 	exp += int(carry)
-	if lor <= 1<<31 && hir > 1<<31 || hip != lop {
-		u64, offset, err := bigParseFloat(num, 0)
-		return math.Float32frombits(uint32(u64)), offset, err
-	}
-
 	if exp >= 0x0ff {
-		return math.Float32frombits(inf32 | uint32(sign)<<31), offset, errorRange(fnc, num)
+		bit := uint32(inf32)
+		if !p.toInf(sign) {
+			bit--
+		}
+		return math.Float32frombits(bit | uint32(sign)<<31), offset, true, errorOf(fnc, num, p.overflow())
 	}
 
 	bit := lop & (1<<prec - 1)
 	bit |= uint32(exp) << prec
 	bit |= uint32(sign) << 31
-	return math.Float32frombits(bit), offset, nil
+	if exp == 0 && p.FlushToZero {
+		bit = uint32(sign) << 31
+	}
+	if exp == 0 && p.Underflow {
+		return math.Float32frombits(bit), offset, true, errorOf(fnc, num, ErrUnderflow)
+	}
+	return math.Float32frombits(bit), offset, true, nil
 }
@@ -15,18 +15,75 @@ var (
 	}
 )
 
-func parseFloat64(num string) (float64, int, error) {
+// max exponent + "mant" variable size + subnormal range.
+// all parts are taken as upper bounds.
+const limit64 = 308 + 20 + 20
+
+func parseFloat64[T text](num T, p *Parser) (float64, int, error) {
+	const fnc = "ParseFloat"
+	f64, offset, inexact, err := read64(num, p)
+	if err == nil && inexact && p.Exact {
+		err = errorOf(fnc, num, ErrInexact)
+	}
+	return f64, offset, err
+}
+
+// read64 is parseFloat64, but returns whether the result is inexact instead
+// of ErrInexact. that is only known when p.Exact is set.
+func read64[T text](num T, p *Parser) (float64, int, bool, error) {
+	const fnc = "ParseFloat"
+	dec, done, f64, offset, err := scan64(num, p, limit64)
+	if done {
+		return f64, offset, dec.inexact, err
+	}
+	u64, ok, inexact, err := compose64(dec.mant, dec.exp10, dec.trunc, dec.sign, p)
+	if !ok {
+		u64, inexact, err = bigParseFloat(num[:offset], p, binary64)
+	}
+	return math.Float64frombits(u64), offset, inexact, errorOf(fnc, num, err)
+}
+
+// a scan is a decimal number read by scan64,
+// which is mant*10^exp10 with the sign.
+type scan struct {
+	mant  uint64
+	exp10 int
+	sign  int
+	// trunc reports whether non-zero digits were dropped after mant,
+	// and drop is the number of digits dropped, zeros included.
+	trunc bool
+	drop  int
+	// num[begin:end] is the mantissa as written.
+	begin, end int
+	// hex reports whether num is a hexadecimal, which is converted,
+	// and inexact whether that is inexact.
+	hex     bool
+	inexact bool
+	// cut reports whether the exponent is beyond the limit of scan64.
+	cut bool
+}
+
+// scan64 reads num up to where the number ends, and returns it as a scan.
+// Infs, NaNs and hexadecimals are converted there, and returned with done
+// set, and so are syntax errors. exponents beyond ±limit are cut off to it.
+func scan64[T text](num T, p *Parser, limit int) (scan, bool, float64, int, error) {
 	const fnc = "ParseFloat"
 	var (
 		sign  int
 		mant  uint64
 		exp10 int
+		trunc bool
+		drop  int
+		cut   bool
 	)
 
 	var offset int
 	if offset >= len(num) {
-		return 0, 0, errorSyntax(fnc, num)
+		return scan{}, true, 0, 0, errorSyntax(fnc, num, offset, ErrMantissa)
 	} else if num[offset] == '+' {
+		if p.NoLeadingPlus {
+			return scan{}, true, 0, 0, errorSyntax(fnc, num, offset, ErrNotAllowed)
+		}
 		offset++
 	} else if num[offset] == '-' {
 		offset++
@@ -34,45 +91,75 @@ func parseFloat64(num string) (float64, int, error) {
 	}
 
 	if offset >= len(num) {
-		return 0, 0, errorSyntax(fnc, num)
+		return scan{}, true, 0, 0, errorSyntax(fnc, num, offset, ErrMantissa)
+	}
+	if len(p.Specials) != 0 {
+		if spec, read := special(num[offset:], p); read != 0 {
+			if spec.NaN {
+				return scan{}, true, math.Float64frombits(0x7ff8<<48 | uint64(sign)<<63), offset + read, nil
+			}
+			return scan{}, true, math.Inf(-sign), offset + read, nil
+		}
 	}
+
 	// ORing 0x20 gives lowercased characters.
-	if num[offset]|0x20 == 'i' {
-		comm := common(num[offset+1:], "nfinity")
-		if comm == 7 {
-			return math.Inf(-sign), offset + 8, nil
+	if num[offset]|0x20 == 'i' && !p.NoSpecials {
+		comm := common(num[offset:], "Infinity", p.CaseSensitive)
+		if comm == 8 {
+			return scan{}, true, math.Inf(-sign), offset + 8, nil
 		}
-		if comm == 2 {
-			return math.Inf(-sign), offset + 3, nil
+		if comm >= 3 {
+			// "infi" and "infinit" are read as "inf" with a suffix.
+			return scan{}, true, math.Inf(-sign), offset + 3, nil
 		}
-		return 0, 0, errorSyntax(fnc, num)
+		return scan{}, true, 0, 0, errorSyntax(fnc, num, offset, ErrMantissa)
+	}
+
+	if p.NaNPayloads && !p.NoSpecials && (num[offset]|0x20 == 'n' || num[offset]|0x20 == 's') {
+		// signed, signaling and with payloads, see nan.go.
+		u64, offset, err := nanParseFloat(num, offset, sign, p, binary64)
+		return scan{}, true, math.Float64frombits(u64), offset, err
 	}
 
-	if num[offset]|0x20 == 'n' {
-		comm := common(num[offset+1:], "an")
+	if num[offset]|0x20 == 'n' && !p.NoSpecials {
+		comm := common(num[offset:], "NaN", p.CaseSensitive)
 		// NaN cannot be signed.
-		if comm == 2 && offset == 0 {
-			return math.NaN(), offset + 3, nil
+		if comm == 3 && offset == 0 {
+			return scan{}, true, math.NaN(), offset + 3, nil
+		}
+		if comm == 3 {
+			return scan{}, true, 0, 0, errorSyntax(fnc, num, 0, ErrSignedNaN)
 		}
-		return 0, 0, errorSyntax(fnc, num)
+		return scan{}, true, 0, 0, errorSyntax(fnc, num, offset, ErrMantissa)
 	}
 
-	if offset+1 < len(num) && num[offset] == '0' && num[offset+1]|0x20 == 'x' {
-		u64, offset, err := hexParseFloat(num, 1)
-		return math.Float64frombits(u64), offset, err
+	if offset+1 < len(num) && num[offset] == '0' && num[offset+1]|0x20 == 'x' && !p.NoHex {
+		u64, offset, inexact, err := hexParseFloat(num, p, binary64)
+		return scan{sign: sign, hex: true, inexact: inexact}, true, math.Float64frombits(u64), offset, err
 	}
 
 	// the limit of being able to do
 	// mant = mant*10 + 9.
-	const limit = 0x1999999999999999
+	const full = 0x1999999999999999
 	var point, digit, line bool
+	// mark is the decimal point, and sep is the separator
+	// between digits which is '_' unless a locale sets it.
+	mark, sep, lines := p.notation()
+	// a zero followed by a digit, either directly or through sep.
+	if num[offset] == '0' && p.NoLeadingZeros && offset+1 < len(num) && (num[offset+1]-'0' <= '9'-'0' || num[offset+1] == sep) {
+		return scan{}, true, 0, 0, errorSyntax(fnc, num, offset, ErrNotAllowed)
+	}
+	begin := offset
 	for ; offset < len(num); offset++ {
 		char := num[offset]
-		if char == '.' && !point {
+		if char == mark && !point {
+			if !digit && p.NoLeadingPoint {
+				return scan{}, true, 0, 0, errorSyntax(fnc, num, offset, ErrNotAllowed)
+			}
 			point = true
 			continue
 		}
-		if char == '_' {
+		if char == sep && lines {
 			line = true
 			continue
 		}
@@ -84,7 +171,9 @@ func parseFloat64(num string) (float64, int, error) {
 		if point {
 			exp10--
 		}
-		if mant >= limit {
+		if mant >= full {
+			trunc = trunc || char != 0
+			drop++
 			exp10++
 			continue
 		}
@@ -92,18 +181,21 @@ func parseFloat64(num string) (float64, int, error) {
 	}
 
 	if !digit {
-		return 0, 0, errorSyntax(fnc, num)
+		return scan{}, true, 0, 0, errorSyntax(fnc, num, offset, ErrMantissa)
+	}
+	if point && num[offset-1] == mark && p.NoTrailingPoint {
+		return scan{}, true, 0, 0, errorSyntax(fnc, num, offset-1, ErrNotAllowed)
 	}
+	end := offset
 
 	if offset < len(num) && num[offset]|0x20 == 'e' {
-		// max exponent + "mant" variable size + subnormal range.
-		// all parts are taken as upper bounds.
-		const limit = 308 + 20 + 20
-		var shift int
+		// int64 doesn't overflow below 10 times the limit,
+		// which can be math.MaxInt32 even on 32-bit.
+		var shift int64
 		var esign, edigit bool
 		offset++
 		if offset >= len(num) {
-			return 0, 0, errorSyntax(fnc, num)
+			return scan{}, true, 0, 0, errorSyntax(fnc, num, offset, ErrExponent)
 		} else if num[offset] == '+' {
 			offset++
 		} else if offset < len(num) && num[offset] == '-' {
@@ -112,7 +204,7 @@ func parseFloat64(num string) (float64, int, error) {
 		}
 		for ; offset < len(num); offset++ {
 			char := num[offset]
-			if char == '_' {
+			if char == '_' && lines && sep == '_' {
 				line = true
 				continue
 			}
@@ -121,41 +213,57 @@ func parseFloat64(num string) (float64, int, error) {
 				break
 			}
 			edigit = true
-			// exponent does not matter for 0; just consume.
-			if mant == 0 {
-				continue
-			}
 			// definitely an overflow
-			if esign && exp10-shift < -limit || !esign && exp10+shift > limit {
+			if esign && int64(exp10)-shift < -int64(limit) || !esign && int64(exp10)+shift > int64(limit) {
 				continue
 			}
-			shift = shift*10 + int(char)
+			shift = shift*10 + int64(char)
 		}
 		if esign {
-			exp10 -= shift
-		} else {
-			exp10 += shift
+			shift = -shift
+		}
+		exp := int64(exp10) + shift
+		if exp > int64(limit) || exp < -int64(limit) {
+			exp = min(max(exp, -int64(limit)), int64(limit))
+			cut = true
 		}
+		exp10 = int(exp)
 		if !edigit {
-			return 0, 0, errorSyntax(fnc, num)
+			return scan{}, true, 0, 0, errorSyntax(fnc, num, offset, ErrExponent)
 		}
 	}
 
-	if line {
-		for idx := 0; idx < len(num); idx++ {
+	if line && p.Locale.Group != 0 {
+		if idx := grouped(num[begin:end], mark, sep, p.Locale.Indian); idx >= 0 {
+			return scan{}, true, 0, 0, errorSyntax(fnc, num, begin+idx, ErrSeparator)
+		}
+	} else if line {
+		// only the part we consumed is checked; the rest is
+		// left for the caller of ParseFloatPrefix.
+		for idx := 0; idx < offset; idx++ {
 			if num[idx] != '_' {
 				continue
 			}
-			if idx == 0 || idx == len(num)-1 {
-				return 0, 0, errorSyntax(fnc, num)
+			if idx == 0 || idx == offset-1 {
+				return scan{}, true, 0, 0, errorSyntax(fnc, num, idx, ErrSeparator)
 			}
 			lo, hi := num[idx-1], num[idx+1]
 			if lo-'0' > '9'-'0' || hi-'0' > '9'-'0' {
-				return 0, 0, errorSyntax(fnc, num)
+				return scan{}, true, 0, 0, errorSyntax(fnc, num, idx, ErrSeparator)
 			}
 		}
 	}
+	dec := scan{mant: mant, exp10: exp10, sign: sign, trunc: trunc, drop: drop, begin: begin, end: end, cut: cut}
+	return dec, false, 0, offset, nil
+}
 
+// compose64 returns the bits of mant*10^exp10 in binary64, rounded in the
+// mode of p, where trunc reports whether mant is cut off from the digits.
+// ok is false when the bounds can't decide the result, and the caller has to
+// go the slow path. whether the result is inexact is only known when p doesn't
+// round to nearest, see Parser.nearest. the error is returned as is, like
+// bigParseFloat.
+func compose64(mant uint64, exp10 int, trunc bool, sign int, p *Parser) (uint64, bool, bool, error) {
 	abs := max(exp10, -exp10)
 	if abs <= 22 && mant < 1<<53 {
 		// even if it can't represent the number exactly,
@@ -167,15 +275,44 @@ func parseFloat64(num string) (float64, int, error) {
 		} else {
 			f64 /= pow10float64[abs]
 		}
-		if sign > 0 {
-			f64 = -f64
+		if p.nearest() {
+			if sign > 0 {
+				f64 = -f64
+			}
+			return math.Float64bits(f64), true, false, nil
+		}
+		// the hardware only rounds to nearest even and doesn't tell whether
+		// it did. the exact value minus f64 (times 10^abs for divisions) has
+		// the right sign with FMA, and tells which neighbor is the other one.
+		var diff float64
+		if exp10 > 0 {
+			diff = math.FMA(float64(mant), pow10float64[abs], -f64)
+		} else {
+			diff = math.FMA(-f64, pow10float64[abs], float64(mant))
+		}
+		u64 := math.Float64bits(f64)
+		if diff == 0 {
+			return u64 | uint64(sign)<<63, true, false, nil
+		}
+		var up uint64
+		if diff < 0 {
+			up = 1
+		}
+		lo := u64 - up
+		// quotients are never ties, since they'd be exact. products are
+		// integers, and diff is exact.
+		ulp := math.Float64frombits(lo+1) - math.Float64frombits(lo)
+		tie := exp10 > 0 && math.Abs(diff)*2 == ulp
+		var half uint64
+		if up != 0 || tie {
+			half = 1
 		}
-		return f64, offset, nil
+		u64 = lo + round(lo<<1|half, !tie, sign, p.Rounding)
+		return u64 | uint64(sign)<<63, true, true, nil
 	}
 
 	if mant == 0 {
-		f64 := math.Float64frombits(uint64(sign) << 63)
-		return f64, offset, nil
+		return uint64(sign) << 63, true, false, nil
 	}
 
 	exp := exp10
@@ -197,8 +334,8 @@ func parseFloat64(num string) (float64, int, error) {
 	zero := bits.LeadingZeros64(mant)
 	lom := mant << zero
 	him := lom
-	if mant >= limit {
-		// mant >= limit when it couldn't represent
+	if trunc {
+		// trunc is set when it couldn't represent
 		// the mantissa exactly. create an upper bound.
 		him += 1 << zero
 	}
@@ -251,39 +388,63 @@ func parseFloat64(num string) (float64, int, error) {
 		exp = 0
 	}
 
-	hir := hip << (prec + 1)
-	lor := lop << (prec + 1)
-	hip = hip>>(63-prec) + hir>>63
-	lop = lop>>(63-prec) + lor>>63
+	// slow == true: the bounds don't agree on the result.
+	var slow bool
+	if p.nearest() {
+		hir := hip << (prec + 1)
+		lor := lop << (prec + 1)
+		hip = hip>>(63-prec) + hir>>63
+		lop = lop>>(63-prec) + lor>>63
+		// the former condition ensures there is no possibilities
+		// of ending up in the "ties".
+		slow = lor <= 1<<63 && hir > 1<<63 || hip != lop
+	} else {
+		// other modes (and Exact) need the bounds to agree on the bit
+		// below the result too, and the lower bound to have non-zero
+		// bits below that, which means the result is not exact.
+		slow = lop>>(62-prec) != hip>>(62-prec) || lop<<(prec+2) == 0
+		lop = lop>>(63-prec) + round(lop>>(62-prec), true, sign, p.Rounding)
+	}
+	if slow {
+		return 0, false, false, nil
+	}
 
 	if lop>>prec != 0 && exp == 0 {
 		exp++
 	}
 
 	carry := lop >> (prec + 1)
-	hip >>= carry
 	lop >>= carry
 	exp += int(carry)
-	if lor <= 1<<63 && hir > 1<<63 || hip != lop {
-		// the former condition ensures there is no possibilities
-		// of ending up in the "ties".
-		u64, offset, err := bigParseFloat(num, 1)
-		return math.Float64frombits(u64), offset, err
-	}
-
 	if exp >= 0x7ff {
-		return math.Inf(-sign), offset, errorRange(fnc, num)
+		bit := uint64(0x7ff) << prec
+		if !p.toInf(sign) {
+			// the largest finite number is just below Inf.
+			bit--
+		}
+		return bit | uint64(sign)<<63, true, true, p.overflow()
 	}
 
 	bit := lop & (1<<prec - 1)
 	bit |= uint64(exp) << prec
 	bit |= uint64(sign) << 63
-	return math.Float64frombits(bit), offset, nil
+	if exp == 0 && p.FlushToZero {
+		// subnormals become zeros of the same sign.
+		bit = uint64(sign) << 63
+	}
+	// outside of nearest, we only get here with inexact results, see above.
+	if exp == 0 && p.Underflow {
+		return bit, true, true, ErrUnderflow
+	}
+	return bit, true, true, nil
 }
 
-func common(str, cmp string) int {
+// common returns the length of the common prefix of str and cmp.
+// letters are compared ignoring case unless exact is true.
+func common[T text](str T, cmp string, exact bool) int {
 	for idx := 0; idx < len(str) && idx < len(cmp); idx++ {
-		if str[idx]|0x20 != cmp[idx] {
+		// only letters are folded; ORing 0x20 changes other bytes too.
+		if str[idx] != cmp[idx] && (exact || str[idx]|0x20 != cmp[idx]|0x20 || cmp[idx]|0x20-'a' > 'z'-'a') {
 			return idx
 		}
 	}
@@ -0,0 +1,38 @@
+package refloat
+
+// ParseFloat8E4M3 converts num to E4M3, the 8-bit floating-point format of
+// the OCP (Open Compute Project) with 4 bits of exponent and 3 of mantissa,
+// and returns its bits. num has the same syntax as for ParseFloat, and the
+// result is correctly rounded.
+//
+// E4M3 has no Inf, and 0x7f and 0xff are its only NaNs, so the largest
+// finite number is 448. The Overflow of a Parser chooses between the modes
+// of the OCP specification: OverflowInf is the non-saturating mode, where
+// values too large and Infs become NaN, and the others are the saturating
+// mode, where they become ±448. Values too large are ErrRange unless the
+// policy is OverflowSaturateSilent; Infs are not an error.
+func ParseFloat8E4M3(num string) (uint8, error) {
+	return std.ParseFloat8E4M3(num)
+}
+
+// ParseFloat8E5M2 is like ParseFloat8E4M3, but for E5M2, which has 5 bits of
+// exponent and 2 of mantissa. E5M2 follows IEEE-754, so values too large
+// become Inf in the non-saturating mode. In the saturating mode, they
+// become ±57344, the largest finite number, and so do Infs.
+func ParseFloat8E5M2(num string) (uint8, error) {
+	return std.ParseFloat8E5M2(num)
+}
+
+// ParseFloat8E4M3 is like the package-level ParseFloat8E4M3, but with the syntax of p.
+func (p *Parser) ParseFloat8E4M3(num string) (uint8, error) {
+	const fnc = "ParseFloat8E4M3"
+	u64, err := parseNarrow(num, p, e4m3, fnc)
+	return uint8(u64), err
+}
+
+// ParseFloat8E5M2 is like the package-level ParseFloat8E5M2, but with the syntax of p.
+func (p *Parser) ParseFloat8E5M2(num string) (uint8, error) {
+	const fnc = "ParseFloat8E5M2"
+	u64, err := parseNarrow(num, p, e5m2, fnc)
+	return uint8(u64), err
+}
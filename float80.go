@@ -0,0 +1,47 @@
+package refloat
+
+// A Float80 is the bits of an x87 extended precision number, the long double
+// of C on x86. In memory, Mant comes first in little-endian, followed by SignExp.
+//
+// Mant has the integer bit at the top, which is stored unlike the IEEE-754
+// formats, and 63 bits of fraction below it. SignExp has the sign at the top
+// and the 15-bit exponent below it.
+type Float80 struct {
+	Mant    uint64
+	SignExp uint16
+}
+
+var (
+	binary80 = format{width: 80, prec: 63, bias: 16383, explicit: true}
+)
+
+// ParseFloat80 converts num to an x87 extended precision number.
+// num has the same syntax as for ParseFloat, and the result is correctly
+// rounded to nearest even.
+//
+// The results are always in the encodings the x87 produces itself:
+// normal numbers have the integer bit, and subnormals and zeros don't.
+// Pseudo-denormals, unnormals, pseudo-Infs and pseudo-NaNs are never
+// returned.
+//
+// The errors are the same as ParseFloat, for the range of the format.
+func ParseFloat80(num string) (Float80, error) {
+	return std.ParseFloat80(num)
+}
+
+// ParseFloat80 is like the package-level ParseFloat80, but with the syntax of p.
+func (p *Parser) ParseFloat80(num string) (Float80, error) {
+	const fnc = "ParseFloat80"
+	hi, lo, err := parseWide(num, p, binary80, fnc)
+	return Float80{Mant: lo, SignExp: uint16(hi)}, err
+}
+
+// FormatFloat80 returns the shortest decimal that ParseFloat80 reads back
+// as the value of f, in the format of strconv.FormatFloat(f, 'g', -1, 64).
+//
+// Like Intel processors since the 80387, it reads pseudo-denormals as
+// numbers with the smallest exponent, which ParseFloat80 returns as normal
+// numbers, and unnormals, pseudo-Infs and pseudo-NaNs as NaNs.
+func FormatFloat80(f Float80) string {
+	return formatWide(uint64(f.SignExp), f.Mant, binary80)
+}
@@ -0,0 +1,156 @@
+package refloat_test
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+var float80tests = []struct {
+	inp string
+	out Float80
+	err error
+}{
+	{"1", Float80{0x8000000000000000, 0x3fff}, nil},
+	{"-2", Float80{0x8000000000000000, 0xc000}, nil},
+	{"0.1", Float80{0xcccccccccccccccd, 0x3ffb}, nil},
+	{"3.14159265358979323846", Float80{0xc90fdaa22168c235, 0x4000}, nil},
+	{"1.18973149535723176502e4932", Float80{0xffffffffffffffff, 0x7ffe}, nil},
+	{"1.2e4932", Float80{0x8000000000000000, 0x7fff}, ErrRange},
+	{"3.36210314311209350626e-4932", Float80{0x8000000000000000, 0x0001}, nil},
+	// the largest subnormal, which has no integer bit.
+	{"3.362103143112093506e-4932", Float80{0x7fffffffffffffff, 0x0000}, nil},
+	{"3.64519953188247460253e-4951", Float80{0x0000000000000001, 0x0000}, nil},
+	{"1.8e-4951", Float80{0, 0}, nil},
+	{"1.9e-4951", Float80{0x0000000000000001, 0x0000}, nil},
+	{"-0", Float80{0, 0x8000}, nil},
+	{"0x1.fffffffffffffffep0", Float80{0xffffffffffffffff, 0x3fff}, nil},
+	// the tie rounds to even, which carries into the exponent.
+	{"0x1.ffffffffffffffffp0", Float80{0x8000000000000000, 0x4000}, nil},
+	{"0x1.0000000000000001p0", Float80{0x8000000000000000, 0x3fff}, nil},
+	{"0x1.00000000000000011p0", Float80{0x8000000000000001, 0x3fff}, nil},
+	{"inf", Float80{0x8000000000000000, 0x7fff}, nil},
+	{"-Infinity", Float80{0x8000000000000000, 0xffff}, nil},
+	{"nan", Float80{0xc000000000000000, 0x7fff}, nil},
+	{"1e", Float80{}, ErrSyntax},
+}
+
+func TestParseFloat80(t *testing.T) {
+	for _, test := range float80tests {
+		out, err := ParseFloat80(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("ParseFloat80(%q) = %#x, %v want %#x, %v", test.inp, out, err, test.out, test.err)
+		}
+	}
+	_, err := ParseFloat80("1.5e")
+	var num *NumError
+	if !errors.As(err, &num) || num.Func != "ParseFloat80" || !errors.Is(err, ErrExponent) {
+		t.Errorf("ParseFloat80(%q) = _, %#v; want a *NumError for ParseFloat80", "1.5e", err)
+	}
+}
+
+func TestParseFloat80Parser(t *testing.T) {
+	for _, test := range []struct {
+		par Parser
+		inp string
+		out Float80
+		err error
+	}{
+		{Parser{Exact: true}, "0.1", Float80{0xcccccccccccccccd, 0x3ffb}, ErrInexact},
+		{Parser{Rounding: big.ToZero}, "0.1", Float80{0xcccccccccccccccc, 0x3ffb}, nil},
+		{Parser{Overflow: OverflowSaturate}, "-1e5000", Float80{0xffffffffffffffff, 0xfffe}, ErrRange},
+		{Parser{FlushToZero: true}, "1e-4940", Float80{0, 0}, nil},
+		{Parser{NaNPayloads: true}, "-nan(5)", Float80{0xc000000000000005, 0xffff}, nil},
+		{Parser{NaNPayloads: true}, "snan", Float80{0x8000000000000001, 0x7fff}, nil},
+		{Parser{NaNPayloads: true}, "nan(0x4000000000000000)", Float80{0xc000000000000000, 0x7fff}, ErrRange},
+	} {
+		out, err := test.par.ParseFloat80(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("%+v.ParseFloat80(%q) = %#x, %v want %#x, %v", test.par, test.inp, out, err, test.out, test.err)
+		}
+	}
+}
+
+func TestFormatFloat80(t *testing.T) {
+	for _, test := range []struct {
+		out string
+		inp Float80
+	}{
+		{"1", Float80{0x8000000000000000, 0x3fff}},
+		{"0.1", Float80{0xcccccccccccccccd, 0x3ffb}},
+		{"1.189731495357231765e+4932", Float80{0xffffffffffffffff, 0x7ffe}},
+		{"4e-4951", Float80{0x0000000000000001, 0x0000}},
+		{"-Inf", Float80{0x8000000000000000, 0xffff}},
+		{"NaN", Float80{0xc000000000000000, 0x7fff}},
+		// pseudo-denormals are read with the smallest exponent.
+		{"3.3621031431120935063e-4932", Float80{0x8000000000000000, 0x0000}},
+		{"-3.3621031431120935066e-4932", Float80{0x8000000000000001, 0x8000}},
+		// unnormals, pseudo-Infs and pseudo-NaNs.
+		{"NaN", Float80{0x4000000000000000, 0x0001}},
+		{"NaN", Float80{0, 0x7fff}},
+		{"NaN", Float80{0x4000000000000000, 0x7fff}},
+	} {
+		if out := FormatFloat80(test.inp); out != test.out {
+			t.Errorf("FormatFloat80(%#x) = %q want %q", test.inp, out, test.out)
+		}
+	}
+}
+
+func TestFloat80RoundTrip(t *testing.T) {
+	try := 1000
+	if testing.Short() {
+		try = 50
+	}
+	for ; try > 0; try-- {
+		inp := Float80{rand.Uint64(), uint16(rand.Uint32())}
+		if inp.SignExp&0x7fff == 0x7fff {
+			continue
+		}
+		// the integer bit is set only for normal numbers.
+		inp.Mant &^= 1 << 63
+		if inp.SignExp&0x7fff != 0 {
+			inp.Mant |= 1 << 63
+		}
+		str := FormatFloat80(inp)
+		if out, err := ParseFloat80(str); out != inp || err != nil {
+			t.Fatalf("ParseFloat80(FormatFloat80(%#x)) = ParseFloat80(%q) = %#x, %v", inp, str, out, err)
+		}
+	}
+}
+
+// the normal range is compared with big.Float, which rounds correctly.
+func TestFloat80Rounding(t *testing.T) {
+	try := 2000
+	if testing.Short() {
+		try = 200
+	}
+	for ; try > 0; try-- {
+		// random digits, and midpoints of neighbors.
+		mant := new(big.Int).Rand(rand.New(rand.NewSource(int64(try))), new(big.Int).Lsh(big.NewInt(1), uint(rand.Intn(100)+1)))
+		exp := rand.Intn(600) - 300
+		if try%2 == 0 {
+			mant.SetBit(mant, 65, 1)
+			mant.Rsh(mant, uint(mant.BitLen()-65))
+			mant.SetBit(mant, 0, 1)
+		}
+		rat := new(big.Rat).SetInt(mant)
+		if exp > 0 {
+			rat.Mul(rat, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(exp))))
+		} else {
+			rat.Quo(rat, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(-exp))))
+		}
+		if rat.Sign() == 0 {
+			continue
+		}
+		str := exactDecimal(rat)
+		want := new(big.Float).SetPrec(64).SetRat(rat)
+		out, err := ParseFloat80(str)
+		got := new(big.Float).SetMantExp(new(big.Float).SetUint64(out.Mant), int(out.SignExp&0x7fff)-16383-63)
+		if err != nil || got.Cmp(want) != 0 {
+			t.Fatalf("ParseFloat80(%q) = %#x, %v want %v", str, out, err, want.Text('p', 0))
+		}
+	}
+}
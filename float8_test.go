@@ -0,0 +1,97 @@
+package refloat_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+var float8s = []minifloat{
+	{"Float8E4M3", func(par *Parser, inp string) (uint16, error) {
+		out, err := par.ParseFloat8E4M3(inp)
+		return uint16(out), err
+	}, nil, 3, 7, 0x7f, 0x80},
+	{"Float8E5M2", func(par *Parser, inp string) (uint16, error) {
+		out, err := par.ParseFloat8E5M2(inp)
+		return uint16(out), err
+	}, nil, 2, 15, 0x7c, 0x80},
+}
+
+type float8Test struct {
+	par Parser
+	inp string
+	out uint8
+	err error
+}
+
+var e4m3tests = []float8Test{
+	{Parser{}, "1", 0x38, nil},
+	{Parser{}, "-2", 0xc0, nil},
+	{Parser{}, "0x1.2p0", 0x39, nil},
+	{Parser{}, "0.1", 0x1d, nil},
+	{Parser{}, "0.015625", 0x08, nil},
+	{Parser{}, "0.001953125", 0x01, nil},
+	{Parser{}, "0x1p-10", 0x00, nil},
+	{Parser{}, "-0x1.0000001p-10", 0x81, nil},
+	{Parser{}, "448", 0x7e, nil},
+	{Parser{}, "256", 0x78, nil},
+	{Parser{}, "464", 0x7e, nil},
+	{Parser{}, "464.0000001", 0x7f, ErrRange},
+	{Parser{}, "-1e10", 0xff, ErrRange},
+	{Parser{}, "inf", 0x7f, nil},
+	{Parser{}, "-inf", 0xff, nil},
+	{Parser{}, "nan", 0x7f, nil},
+	{Parser{NaNPayloads: true}, "-nan(1)", 0xff, nil},
+	{Parser{Overflow: OverflowSaturate}, "500", 0x7e, ErrRange},
+	{Parser{Overflow: OverflowSaturate}, "-1e400", 0xfe, ErrRange},
+	{Parser{Overflow: OverflowSaturateSilent}, "500", 0x7e, nil},
+	{Parser{Overflow: OverflowSaturate}, "inf", 0x7e, nil},
+	{Parser{Overflow: OverflowSaturate}, "-Infinity", 0xfe, nil},
+	{Parser{Overflow: OverflowSaturate}, "nan", 0x7f, nil},
+	{Parser{Rounding: big.ToZero}, "500", 0x7e, ErrRange},
+	{Parser{}, "1e", 0, ErrSyntax},
+}
+
+var e5m2tests = []float8Test{
+	{Parser{}, "1", 0x3c, nil},
+	{Parser{}, "-0.1", 0xae, nil},
+	{Parser{}, "57344", 0x7b, nil},
+	{Parser{}, "61439", 0x7b, nil},
+	{Parser{}, "61440", 0x7c, ErrRange},
+	{Parser{}, "0x1p-16", 0x01, nil},
+	{Parser{}, "0x1p-17", 0x00, nil},
+	{Parser{}, "inf", 0x7c, nil},
+	{Parser{}, "-inf", 0xfc, nil},
+	{Parser{}, "nan", 0x7e, nil},
+	{Parser{NaNPayloads: true}, "snan", 0x7d, nil},
+	{Parser{NaNPayloads: true}, "nan(1)", 0x7f, nil},
+	{Parser{NaNPayloads: true}, "nan(2)", 0x7e, ErrRange},
+	{Parser{Overflow: OverflowSaturate}, "61440", 0x7b, ErrRange},
+	{Parser{Overflow: OverflowSaturateSilent}, "-1e10", 0xfb, nil},
+	{Parser{Overflow: OverflowSaturate}, "inf", 0x7b, nil},
+	{Parser{Exact: true}, "0.1", 0x2e, ErrInexact},
+	{Parser{Underflow: true}, "0x1p-15", 0x02, ErrUnderflow},
+}
+
+func TestParseFloat8(t *testing.T) {
+	for _, test := range e4m3tests {
+		out, err := test.par.ParseFloat8E4M3(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("%+v.ParseFloat8E4M3(%q) = %#02x, %v want %#02x, %v", test.par, test.inp, out, err, test.out, test.err)
+		}
+	}
+	for _, test := range e5m2tests {
+		out, err := test.par.ParseFloat8E5M2(test.inp)
+		if out != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("%+v.ParseFloat8E5M2(%q) = %#02x, %v want %#02x, %v", test.par, test.inp, out, err, test.out, test.err)
+		}
+	}
+}
+
+func TestFloat8Rounding(t *testing.T) {
+	for _, typ := range float8s {
+		testMiniRounding(t, typ)
+	}
+}
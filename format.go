@@ -0,0 +1,179 @@
+package refloat
+
+import (
+	"math"
+	"math/bits"
+)
+
+// a format is a binary floating-point format like the ones of IEEE-754,
+// with an implicit bit, and the exponent with all bits set for Infs and NaNs.
+type format struct {
+	width    int  // the number of bits in total.
+	prec     int  // the number of bits of the mantissa, without the implicit bit.
+	bias     int  // the exponent bias.
+	noInf    bool // no Inf; the largest exponent is for finite numbers too, except for NaN with all bits set.
+	satInf   bool // Infs become the largest finite number in the saturating overflow policies.
+	explicit bool // the integer bit is stored above the mantissa, as in x87; only for pack128.
+}
+
+var (
+	binary64 = format{width: 64, prec: 52, bias: 1023}
+	binary32 = format{width: 32, prec: 23, bias: 127}
+	binary16 = format{width: 16, prec: 10, bias: 15}
+	bfloat16 = format{width: 16, prec: 7, bias: 127}
+	// the 8-bit formats of the OCP (Open Compute Project).
+	e4m3 = format{width: 8, prec: 3, bias: 7, noInf: true, satInf: true}
+	e5m2 = format{width: 8, prec: 2, bias: 15, satInf: true}
+)
+
+// top returns the bits of Inf, or of NaN if f has no Inf.
+// all the bits below it are finite numbers.
+func (f format) top() uint64 {
+	top := (uint64(1)<<(f.width-f.prec-1) - 1) << f.prec
+	if f.noInf {
+		top |= 1<<f.prec - 1
+	}
+	return top
+}
+
+// pack rounds mant*2^exp to f in the rounding mode of p, and returns its
+// bits and whether they are inexact. trunc reports whether there were
+// non-zero bits below mant. the error is ErrRange, ErrUnderflow or nil as
+// is, so the caller can make a NumError with the whole input.
+func (f format) pack(mant uint64, exp int, trunc bool, sign int, p *Parser) (uint64, bool, error) {
+	prec, bias, width := f.prec, f.bias, f.width
+	if mant == 0 {
+		// the MSB is the sign bit. width -1 brings us just that.
+		return uint64(sign) << (width - 1), trunc, nil
+	}
+
+	log := bits.Len64(mant) - prec - 1 - 1
+	exp += log + bias + prec + 1
+	// when we right shift, always check for truncated bits.
+	if log > 0 {
+		if mant&(1<<log-1) != 0 {
+			trunc = true
+		}
+		mant >>= log
+	} else {
+		mant <<= -log
+	}
+	if exp <= 0 {
+		if mant&(2<<-exp-1) != 0 {
+			trunc = true
+		}
+		mant >>= 1 - exp
+		exp = 0
+	}
+	inexact := trunc || mant&1 != 0
+	// the lowest bit is only used for rounding, cut it off and
+	// round with it and the truncated bits. see round.go.
+	mant = mant>>1 + round(mant, trunc, sign, p.Rounding)
+	// this is for a case where we first thought it was subnormal,
+	// but rounding made it slightly higher and made it to normal range.
+	// if that happened, we simply increment exp (mantissa should be kept 0).
+	if mant>>prec != 0 && exp == 0 {
+		exp++
+	}
+	// handle carries from rounding.
+	// mant >> (prec+1) becomes 1 when it overflows.
+	carry := mant >> (prec + 1)
+	mant >>= carry
+	exp += int(carry)
+	// implicit bit; hide the highest 1.
+	mant &= 1<<prec - 1
+	// the bits from top are for Infs and NaNs. exp can be too large
+	// to shift, so it's compared first.
+	top := f.top()
+	inf := int(top >> prec)
+	if exp > inf || exp == inf && mant >= top&(1<<prec-1) {
+		bit := top
+		if !p.toInf(sign) {
+			// the largest finite number is just below Inf.
+			bit--
+		}
+		return bit | uint64(sign)<<(width-1), true, p.overflow()
+	}
+	mant |= uint64(exp) << prec
+	mant |= uint64(sign) << (width - 1)
+	if exp == 0 && p.FlushToZero {
+		// subnormals become zeros of the same sign.
+		// mant is not zero, so it's always inexact.
+		mant, inexact = uint64(sign)<<(width-1), true
+	}
+	if exp == 0 && p.Underflow {
+		return mant, inexact, ErrUnderflow
+	}
+	return mant, inexact, nil
+}
+
+// narrow rounds the float64 bits to f in the rounding mode of p.
+func (f format) narrow(u64 uint64, p *Parser) (uint64, error) {
+	sign := int(u64 >> 63)
+	exp := int(u64 >> 52 & 0x7ff)
+	mant := u64 & (1<<52 - 1)
+	switch {
+	case exp == 0x7ff && mant == 0:
+		bit := f.top()
+		if f.satInf && p.Overflow != OverflowInf {
+			bit--
+		}
+		return bit | uint64(sign)<<(f.width-1), nil
+	case exp == 0x7ff:
+		bit := f.top() | uint64(sign)<<(f.width-1)
+		if f.noInf {
+			// the only NaN has all bits set.
+			return bit, nil
+		}
+		// NaNs keep the quiet bit and the payload, if they are asked for.
+		quiet := uint64(1) << (f.prec - 1)
+		if !p.NaNPayloads {
+			return bit | quiet, nil
+		}
+		load := mant & (1<<51 - 1)
+		var err error
+		if load >= quiet {
+			err = ErrRange
+		}
+		load &= quiet - 1
+		if mant>>51 != 0 {
+			load |= quiet
+		} else if load == 0 {
+			// zero would be Inf.
+			load = 1
+		}
+		return bit | load, err
+	case exp == 0:
+		exp = 1
+	default:
+		mant |= 1 << 52
+	}
+	mant, inexact, err := f.pack(mant, exp-1023-52, false, sign, p)
+	return mant, p.check(inexact, err)
+}
+
+// widen returns the bits of f as a float64, which is always exact.
+func (f format) widen(u64 uint64) float64 {
+	inf := 1<<(f.width-f.prec-1) - 1
+	sign := u64 >> (f.width - 1) & 1
+	exp := int(u64>>f.prec) & inf
+	mant := u64 & (1<<f.prec - 1)
+	var f64 float64
+	switch {
+	case f.noInf && exp == inf && mant == 1<<f.prec-1:
+		return math.NaN()
+	case !f.noInf && exp == inf:
+		f64 = math.Inf(1)
+		if mant != 0 {
+			return math.NaN()
+		}
+	case exp == 0:
+		f64 = math.Ldexp(float64(mant), 1-f.bias-f.prec)
+	default:
+		f64 = math.Ldexp(float64(mant|1<<f.prec), exp-f.bias-f.prec)
+	}
+	if sign != 0 {
+		f64 = -f64
+	}
+	return f64
+}
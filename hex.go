@@ -1,12 +1,7 @@
 package refloat
 
-import (
-	"math/bits"
-)
-
-func hexParseFloat(num string, extend int) (uint64, int, error) {
+func hexParseFloat[T text](num T, p *Parser, f format) (uint64, int, bool, error) {
 	const fnc = "ParseFloat"
-	width := 32 << extend
 	var (
 		sign int
 		mant uint64
@@ -15,7 +10,7 @@ func hexParseFloat(num string, extend int) (uint64, int, error) {
 
 	var offset int
 	if offset >= len(num) {
-		return 0, 0, errorSyntax(fnc, num)
+		return 0, 0, false, errorSyntax(fnc, num, offset, ErrMantissa)
 	} else if num[offset] == '+' {
 		offset++
 	} else if num[offset] == '-' {
@@ -34,11 +29,14 @@ func hexParseFloat(num string, extend int) (uint64, int, error) {
 	var point, trunc, digit, line bool
 	for ; offset < len(num); offset++ {
 		char := num[offset]
-		if char == '_' {
+		if char == '_' && !p.NoUnderscores {
 			line = true
 			continue
 		}
 		if char == '.' && !point {
+			if !digit && p.NoLeadingPoint {
+				return 0, 0, false, errorSyntax(fnc, num, offset, ErrNotAllowed)
+			}
 			point = true
 			continue
 		}
@@ -51,7 +49,7 @@ func hexParseFloat(num string, extend int) (uint64, int, error) {
 		if point {
 			exp -= hexLen
 		}
-		if mant>>(width-hexLen) != 0 {
+		if mant>>(64-hexLen) != 0 {
 			// truncation of 0 digits doesn't matter.
 			trunc = trunc || char != '0'
 			exp += hexLen
@@ -66,24 +64,27 @@ func hexParseFloat(num string, extend int) (uint64, int, error) {
 	}
 
 	if !digit {
-		return 0, 0, errorSyntax(fnc, num)
+		return 0, 0, false, errorSyntax(fnc, num, offset, ErrMantissa)
+	}
+	if point && num[offset-1] == '.' && p.NoTrailingPoint {
+		return 0, 0, false, errorSyntax(fnc, num, offset-1, ErrNotAllowed)
 	}
 
 	if offset >= len(num) || num[offset]|0x20 != 'p' {
 		// according to strconv.readFloat, exponent
 		// is required in hexadecimal.
-		return 0, 0, errorSyntax(fnc, num)
+		return 0, 0, false, errorSyntax(fnc, num, offset, ErrHexExponent)
 	}
 	offset += len("p") // already checked above.
 
 	// max exponent (of positive or negative) + "mant" width + subnormal range.
-	limit := 1024 + width + width
+	limit := 1024 + 64 + 64
 	var shift int
 	// esign  == true: additional exponent part is negative
 	// edigit == true: we at least saw one digit in exponent.
 	var esign, edigit bool
 	if offset >= len(num) {
-		return 0, 0, errorSyntax(fnc, num)
+		return 0, 0, false, errorSyntax(fnc, num, offset, ErrExponent)
 	} else if num[offset] == '+' {
 		offset++
 	} else if num[offset] == '-' {
@@ -93,7 +94,7 @@ func hexParseFloat(num string, extend int) (uint64, int, error) {
 
 	for ; offset < len(num); offset++ {
 		char := num[offset]
-		if char == '_' {
+		if char == '_' && !p.NoUnderscores {
 			line = true
 			continue
 		}
@@ -120,101 +121,32 @@ func hexParseFloat(num string, extend int) (uint64, int, error) {
 	}
 
 	if !edigit {
-		return 0, 0, errorSyntax(fnc, num)
+		return 0, 0, false, errorSyntax(fnc, num, offset, ErrExponent)
 	}
 
 	if line {
 		// checking syntax related to underlines here
 		// allows us to simply skip those while reading.
 		// underlines often don't exist at all in number literals.
-		for idx := 0; idx < len(num); idx++ {
+		for idx := 0; idx < offset; idx++ {
 			if num[idx] != '_' {
 				continue
 			}
 			// '_' must separate successive digits
 			// note, it does allow you to put them after "0x" prefix.
-			if idx == 0 || idx == len(num)-1 {
-				return 0, 0, errorSyntax(fnc, num)
+			if idx == 0 || idx == offset-1 {
+				return 0, 0, false, errorSyntax(fnc, num, idx, ErrSeparator)
 			}
 			lo, hi := num[idx-1], num[idx+1]
 			if lo|0x20 != 'x' && lo-'0' > '9'-'0' && lo|0x20-'a' > 'f'-'a' {
-				return 0, 0, errorSyntax(fnc, num)
+				return 0, 0, false, errorSyntax(fnc, num, idx, ErrSeparator)
 			}
 			if hi-'0' > '9'-'0' && hi|0x20-'a' > 'f'-'a' {
-				return 0, 0, errorSyntax(fnc, num)
+				return 0, 0, false, errorSyntax(fnc, num, idx, ErrSeparator)
 			}
 		}
 	}
 
-	if mant == 0 {
-		// the MSB is the sign bit. width -1 brings us just that.
-		return uint64(sign) << (width - 1), offset, nil
-	}
-
-	// IEEE-754 mantissa length and exponent bias
-	// for single/doube precision.
-	var prec, bias int
-	switch width {
-	case 64:
-		prec = 52
-		bias = 1023
-	case 32:
-		prec = 23
-		bias = 127
-	}
-
-	log := bits.Len64(mant) - prec - 1 - 1
-	exp += log + bias + prec + 1
-	// when we right shift, always check for truncated bits.
-	// note: mant<<(width-log) != 0 is a wrong implementation,
-	// especially for width == 32.
-	if log > 0 {
-		if mant&(1<<log-1) != 0 {
-			trunc = true
-		}
-		mant >>= log
-	} else {
-		mant <<= -log
-	}
-	if exp <= 0 {
-		if mant&(2<<-exp-1) != 0 {
-			trunc = true
-		}
-		mant >>= 1 - exp
-		exp = 0
-	}
-	// first part: round to nearest.
-	round := mant & 1
-	if round != 0 && !trunc {
-		// second part: ties to even.
-		// if all truncated bits are zero, we're at exactly the middle
-		// of 2 floating points. in that case, go to the closest even
-		// number (mant >> 1).
-		round &= mant >> 1
-	}
-	mant += round
-	// now we don't need the bit we used for rounding, cut off.
-	mant >>= 1
-	// this is for a case where we first thought it was subnormal,
-	// but rounding made it slightly higher and made it to normal range.
-	// if that happened, we simply increment exp (mantissa should be kept 0).
-	if mant>>prec != 0 && exp == 0 {
-		exp++
-	}
-	// handle carries from rounding.
-	// mant >> (prec+1) becomes 1 when it overflows.
-	carry := mant >> (prec + 1)
-	mant >>= carry
-	exp += int(carry)
-	// implicit bit; hide the highest 1.
-	mant &= 1<<prec - 1
-	if extend == 1 && exp >= 0x7ff {
-		return uint64(0x7ff)<<prec | uint64(sign)<<(width-1), offset, errorRange(fnc, num)
-	}
-	if extend == 0 && exp >= 0x0ff {
-		return uint64(0x0ff)<<prec | uint64(sign)<<(width-1), offset, errorRange(fnc, num)
-	}
-	mant |= uint64(exp) << prec
-	mant |= uint64(sign) << (width - 1)
-	return mant, offset, nil
+	u64, inexact, err := f.pack(mant, exp, trunc, sign, p)
+	return u64, offset, inexact, errorOf(fnc, num, err)
 }
@@ -0,0 +1,48 @@
+package refloat
+
+import (
+	"math"
+	"math/big"
+)
+
+// ParseInterval returns the tightest interval [lo, hi] of floating-point
+// numbers of size that contains the exact value of num, which has the same
+// syntax as for ParseFloat. lo == hi exactly when num is representable,
+// and hi is the number next to lo otherwise.
+//
+// For numbers beyond the largest finite number, the interval has an Inf
+// at the end and the error is ErrRange. Infs and NaNs are returned as is,
+// both as lo and hi.
+func ParseInterval(num string, size int) (float64, float64, error) {
+	return std.ParseInterval(num, size)
+}
+
+// ParseInterval is like the package-level ParseInterval, but with the syntax of p.
+func (p *Parser) ParseInterval(num string, size int) (float64, float64, error) {
+	const fnc = "ParseInterval"
+	// lo is rounded toward -Inf, and Exact tells whether it's num itself.
+	// an overflow is inexact too, where lo is the largest number or -Inf.
+	chk := p.with(big.ToNegativeInf)
+	chk.Exact = true
+	lo, read, inexact, err := readFloat(num, size, &chk)
+	lo, err = whole(num, lo, read, err, fnc)
+	if err != nil && err.(*NumError).Err == ErrSyntax {
+		return 0, 0, err
+	}
+	if lo != lo && err != nil {
+		// the payload didn't fit, see Parser.NaNPayloads.
+		return lo, lo, errorRange(fnc, num)
+	}
+	if !inexact {
+		return lo, lo, nil
+	}
+
+	hi := math.Nextafter(lo, math.Inf(1))
+	if size == 32 {
+		hi = float64(math.Nextafter32(float32(lo), float32(math.Inf(1))))
+	}
+	if err != nil {
+		return lo, hi, errorRange(fnc, num)
+	}
+	return lo, hi, nil
+}
@@ -0,0 +1,97 @@
+package refloat_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+func TestParseInterval(t *testing.T) {
+	for _, test := range []struct {
+		inp    string
+		size   int
+		lo, hi float64
+		err    error
+	}{
+		{"1", 64, 1, 1, nil},
+		{"0.5", 64, 0.5, 0.5, nil},
+		{"0.1", 64, 0.09999999999999999, 0.1, nil},
+		{"-0.1", 64, -0.1, -0.09999999999999999, nil},
+		{"0.1", 32, float64(math.Nextafter32(0.1, 0)), float64(float32(0.1)), nil},
+		{"9007199254740993", 64, 9007199254740992, 9007199254740994, nil},
+		{"0x1.00000000000008p0", 64, 1, 1.0000000000000002, nil},
+		{"-0", 64, math.Copysign(0, -1), math.Copysign(0, -1), nil},
+		{"5e-324", 64, 5e-324, 1e-323, nil},
+		{"-0x1p-1074", 64, -5e-324, -5e-324, nil},
+		{"1e-400", 64, 0, 5e-324, nil},
+		{"-1e-400", 64, -5e-324, math.Copysign(0, -1), nil},
+		{"2.2250738585072014e-308", 64, 2.2250738585072014e-308, 2.225073858507202e-308, nil},
+		{"1.7976931348623157e308", 64, 1.7976931348623155e308, math.MaxFloat64, nil},
+		{"1e309", 64, math.MaxFloat64, math.Inf(1), ErrRange},
+		{"-1e309", 64, math.Inf(-1), -math.MaxFloat64, ErrRange},
+		{"1e39", 32, math.MaxFloat32, math.Inf(1), ErrRange},
+		{"inf", 64, math.Inf(1), math.Inf(1), nil},
+		{"1x", 64, 0, 0, ErrSyntax},
+	} {
+		lo, hi, err := ParseInterval(test.inp, test.size)
+		if lo != test.lo || hi != test.hi || math.Signbit(lo) != math.Signbit(test.lo) || math.Signbit(hi) != math.Signbit(test.hi) ||
+			!errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("ParseInterval(%q, %d) = %v, %v, %v want %v, %v, %v", test.inp, test.size, lo, hi, err, test.lo, test.hi, test.err)
+		}
+	}
+	_, _, err := ParseInterval("1.5e", 64)
+	var num *NumError
+	if !errors.As(err, &num) || num.Func != "ParseInterval" || !errors.Is(err, ErrExponent) {
+		t.Errorf("ParseInterval(%q) = _, _, %#v; want a *NumError for ParseInterval", "1.5e", err)
+	}
+}
+
+// the exact value should be in the interval, which is the tightest.
+func TestIntervalContains(t *testing.T) {
+	try := 5000
+	if testing.Short() {
+		try = 500
+	}
+	for ; try > 0; try-- {
+		mant := rand.Uint64() >> rand.Intn(64)
+		exp := rand.Intn(700) - 350
+		size := 32 << rand.Intn(2)
+		if try%4 == 0 {
+			// exact ones.
+			mant, exp = mant>>rand.Intn(64), 0
+		}
+		str := strconv.FormatUint(mant, 10) + "e" + strconv.Itoa(exp)
+		lo, hi, err := ParseInterval(str, size)
+		if err != nil {
+			continue
+		}
+		rat, _ := new(big.Rat).SetString(str)
+		nxt := math.Nextafter(lo, math.Inf(1))
+		if size == 32 {
+			nxt = float64(math.Nextafter32(float32(lo), float32(math.Inf(1))))
+		}
+		low := new(big.Rat).SetFloat64(lo).Cmp(rat)
+		high := new(big.Rat).SetFloat64(hi).Cmp(rat)
+		if low == 0 && lo != hi || low > 0 || high < 0 || lo != hi && hi != nxt {
+			t.Fatalf("ParseInterval(%q, %d) = %v, %v", str, size, lo, hi)
+		}
+	}
+}
+
+func TestParseIntervalAllocs(t *testing.T) {
+	for _, inp := range []string{"0.5", "0.1", "-3.14159", "123e20"} {
+		for _, size := range []int{32, 64} {
+			allocs := testing.AllocsPerRun(100, func() {
+				ParseInterval(inp, size)
+			})
+			if allocs != 0 {
+				t.Errorf("ParseInterval(%q, %d) allocated %v times, want 0", inp, size, allocs)
+			}
+		}
+	}
+}
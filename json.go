@@ -0,0 +1,30 @@
+package refloat
+
+// jsonParser accepts exactly the number grammar of RFC 8259:
+//
+//	number = [ minus ] int [ frac ] [ exp ]
+//	int    = zero / ( digit1-9 *DIGIT )
+//	frac   = decimal-point 1*DIGIT
+//	exp    = e [ minus / plus ] 1*DIGIT
+var jsonParser = Parser{
+	NoUnderscores:   true,
+	NoHex:           true,
+	NoSpecials:      true,
+	NoLeadingPlus:   true,
+	NoLeadingPoint:  true,
+	NoTrailingPoint: true,
+	NoLeadingZeros:  true,
+}
+
+// ParseJSONNumber is like ParseFloat, but num must be a number as defined
+// by RFC 8259, such as "-0.5e+10". Everything ParseFloat accepts on top of
+// it, like "+1", ".5", "5.", "01", "1_000", "0x1p3", "Inf" and "NaN",
+// is an error with err.Err = ErrSyntax.
+//
+// Numbers that are too large for the size are ErrRange just like ParseFloat,
+// since RFC 8259 leaves their handling to the implementation.
+func ParseJSONNumber(num string, size int) (float64, error) {
+	const fnc = "ParseJSONNumber"
+	f64, read, err := parseFloat(num, size, &jsonParser)
+	return whole(num, f64, read, err, fnc)
+}
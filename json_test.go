@@ -0,0 +1,139 @@
+package refloat_test
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+// number cases of JSONTestSuite (https://github.com/nst/JSONTestSuite),
+// with the surrounding array removed. y_ must be accepted, n_ must be
+// rejected, and i_ (implementation defined) are parsed like ParseFloat.
+var jsontests = []atofTest{
+	// y_number_*
+	{"123e65", "1.23e+67", nil},
+	{"0e+1", "0", nil},
+	{"0e1", "0", nil},
+	{"4", "4", nil},
+	{"-0.000000000000000000000000000000000000000000000000000000000000000000000000000000000001", "-1e-84", nil},
+	{"20e1", "200", nil},
+	{"-0", "-0", nil},
+	{"-123", "-123", nil},
+	{"-1", "-1", nil},
+	{"1E22", "1e+22", nil},
+	{"1E-2", "0.01", nil},
+	{"1E+2", "100", nil},
+	{"123e45", "1.23e+47", nil},
+	{"123.456e78", "1.23456e+80", nil},
+	{"1e-2", "0.01", nil},
+	{"1e+2", "100", nil},
+	{"123", "123", nil},
+	{"123.456789", "123.456789", nil},
+
+	// n_number_*
+	{"++1234", "0", ErrSyntax},
+	{"+1", "0", ErrSyntax},
+	{"+Inf", "0", ErrSyntax},
+	{"-01", "0", ErrSyntax},
+	{"-1.0.", "0", ErrSyntax},
+	{"-2.", "0", ErrSyntax},
+	{"-NaN", "0", ErrSyntax},
+	{".-1", "0", ErrSyntax},
+	{".2e-3", "0", ErrSyntax},
+	{"0.1.2", "0", ErrSyntax},
+	{"0.3e+", "0", ErrSyntax},
+	{"0.3e", "0", ErrSyntax},
+	{"0.e1", "0", ErrSyntax},
+	{"0E+", "0", ErrSyntax},
+	{"0E", "0", ErrSyntax},
+	{"0e+", "0", ErrSyntax},
+	{"0e", "0", ErrSyntax},
+	{"1.0e+", "0", ErrSyntax},
+	{"1.0e-", "0", ErrSyntax},
+	{"1.0e", "0", ErrSyntax},
+	{"1 000.0", "0", ErrSyntax},
+	{"1eE2", "0", ErrSyntax},
+	{"2.e+3", "0", ErrSyntax},
+	{"2.e-3", "0", ErrSyntax},
+	{"2.e3", "0", ErrSyntax},
+	{"9.e+", "0", ErrSyntax},
+	{"Inf", "0", ErrSyntax},
+	{"NaN", "0", ErrSyntax},
+	{"１", "0", ErrSyntax},
+	{"1+2", "0", ErrSyntax},
+	{"0x1", "0", ErrSyntax},
+	{"0x42", "0", ErrSyntax},
+	{"Infinity", "0", ErrSyntax},
+	{"0e+-1", "0", ErrSyntax},
+	{"-123.123foo", "0", ErrSyntax},
+	{"123\xe5", "0", ErrSyntax},
+	{"1e1\xe5", "0", ErrSyntax},
+	{"0\xe5", "0", ErrSyntax},
+	{"-Infinity", "0", ErrSyntax},
+	{"-foo", "0", ErrSyntax},
+	{"- 1", "0", ErrSyntax},
+	{"-012", "0", ErrSyntax},
+	{"-.123", "0", ErrSyntax},
+	{"-1x", "0", ErrSyntax},
+	{"1ea", "0", ErrSyntax},
+	{"1e\xe5", "0", ErrSyntax},
+	{"1.", "0", ErrSyntax},
+	{".123", "0", ErrSyntax},
+	{"1.2a-3", "0", ErrSyntax},
+	{"1.8011670033376514H-308", "0", ErrSyntax},
+	{"012", "0", ErrSyntax},
+
+	// i_number_*
+	{"123.456e-789", "0", nil},
+	{"0.4e00669999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999999969999999006", "+Inf", ErrRange},
+	{"-1e+9999", "-Inf", ErrRange},
+	{"1.5e+9999", "+Inf", ErrRange},
+	{"-123123e100000", "-Inf", ErrRange},
+	{"123123e100000", "+Inf", ErrRange},
+	{"123e-10000000", "0", nil},
+	{"-123123123123123123123123123123", "-1.2312312312312312e+29", nil},
+	{"100000000000000000000", "1e+20", nil},
+	{"-237462374673276894279832749832423479823246327846", "-2.374623746732769e+47", nil},
+
+	// the rest of the syntax ParseFloat accepts.
+	{"1_000", "0", ErrSyntax},
+	{"0x1p3", "0", ErrSyntax},
+	{"00", "0", ErrSyntax},
+	{"0_1", "0", ErrSyntax},
+	{"", "0", ErrSyntax},
+	{"-", "0", ErrSyntax},
+	{"0." + strings.Repeat("0", 399) + "1e400", "1", nil},
+}
+
+func TestParseJSONNumber(t *testing.T) {
+	for _, test := range jsontests {
+		out, err := ParseJSONNumber(test.in, 64)
+		outs := strconv.FormatFloat(out, 'g', -1, 64)
+		if outs != test.out || !errors.Is(err, test.err) {
+			t.Errorf("ParseJSONNumber(%q, 64) = %v, %v want %v, %v",
+				test.in, out, err, test.out, test.err)
+		}
+		var num *NumError
+		if err != nil && (!errors.As(err, &num) || num.Func != "ParseJSONNumber") {
+			t.Errorf("ParseJSONNumber(%q, 64) = %#v; want a *NumError for ParseJSONNumber", test.in, err)
+		}
+	}
+}
+
+// every number valid in JSON has the same value as with ParseFloat.
+func TestParseJSONNumberRandom(t *testing.T) {
+	initAtof()
+	for _, test := range atofRandomTests {
+		if math.IsNaN(test.x) || math.IsInf(test.x, 0) {
+			continue
+		}
+		out, err := ParseJSONNumber(test.s, 64)
+		if out != test.x || err != nil {
+			t.Errorf("ParseJSONNumber(%q, 64) = %v, %v want %v, nil", test.s, out, err, test.x)
+		}
+	}
+}
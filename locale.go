@@ -0,0 +1,68 @@
+package refloat
+
+type (
+	// A Locale describes how decimal mantissas are written,
+	// such as "1.234.567,89" or "12,34,567.8".
+	// Hexadecimal mantissas and exponents are not affected.
+	Locale struct {
+		// Point is the decimal mark. The zero value means '.'.
+		Point byte
+		// Group separates groups of digits in the integer part.
+		// If it's set, it replaces '_' and has to be placed every 3 digits
+		// counted from the decimal mark, or not used at all.
+		// The zero value means no grouping. It must differ from Point.
+		Group byte
+		// Indian groups the digits by 2 after the first 3, as in "12,34,567".
+		Indian bool
+	}
+)
+
+// notation returns the decimal mark and the digit separator of p,
+// and whether the separator is accepted at all.
+func (p *Parser) notation() (byte, byte, bool) {
+	mark := p.Locale.Point
+	if mark == 0 {
+		mark = '.'
+	}
+	if p.Locale.Group != 0 {
+		return mark, p.Locale.Group, true
+	}
+	return mark, '_', !p.NoUnderscores
+}
+
+// grouped checks that the separators in the mantissa num are all in
+// the integer part and at the right places. it returns the offset of
+// the first misplaced one, or -1 if there is none.
+func grouped[T text](num T, mark, sep byte, indian bool) int {
+	end := 0
+	for ; end < len(num) && num[end] != mark; end++ {
+	}
+	for idx := end; idx < len(num); idx++ {
+		if num[idx] == sep {
+			return idx
+		}
+	}
+	// count the digits from the mark to the left.
+	size := 3
+	var run int
+	last := -1
+	for idx := end - 1; idx >= 0; idx-- {
+		if num[idx] != sep {
+			run++
+			continue
+		}
+		if run != size {
+			return idx
+		}
+		if indian {
+			size = 2
+		}
+		run = 0
+		last = idx
+	}
+	// the leftmost group can be shorter but not empty.
+	if last >= 0 && (run == 0 || run > size) {
+		return last
+	}
+	return -1
+}
@@ -0,0 +1,131 @@
+package refloat_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+var (
+	localeDE = Locale{Point: ',', Group: '.'}
+	localeFR = Locale{Point: ',', Group: ' '}
+	localeUS = Locale{Point: '.', Group: ','}
+	localeIN = Locale{Point: '.', Group: ',', Indian: true}
+)
+
+type localeTest struct {
+	loc Locale
+	in  string
+	out string
+	err error
+}
+
+var localetests = []localeTest{
+	{localeDE, "1.234.567,89", "1.23456789e+06", nil},
+	{localeDE, "1234567,89", "1.23456789e+06", nil},
+	{localeDE, "-1.234,5e3", "-1.2345e+06", nil},
+	{localeDE, "1,5", "1.5", nil},
+	{localeDE, ",5", "0.5", nil},
+	{localeDE, "123", "123", nil},
+	{localeDE, "1.5", "0", ErrSyntax},
+	{localeDE, "12.34", "0", ErrSyntax},
+	{localeDE, "1.23.456,7", "0", ErrSyntax},
+	{localeDE, "1.2345,6", "0", ErrSyntax},
+	{localeDE, ".123", "0", ErrSyntax},
+	{localeDE, "123.,5", "0", ErrSyntax},
+	{localeDE, "1..234", "0", ErrSyntax},
+	{localeDE, "1,234.5", "0", ErrSyntax},
+	{localeDE, "1_234", "0", ErrSyntax},
+	{localeDE, "1.000.000.000.000.000.000.000.001,5", "1e+24", nil},
+
+	{localeFR, "1 234,5", "1234.5", nil},
+	{localeFR, "-12 345 678,125", "-1.2345678125e+07", nil},
+	{localeFR, "1 2345", "0", ErrSyntax},
+	{localeFR, "1 234 ", "0", ErrSyntax},
+
+	{localeUS, "1,234,567.89", "1.23456789e+06", nil},
+	{localeUS, "1,234.567,8", "0", ErrSyntax},
+	{localeUS, "1_000", "0", ErrSyntax},
+	{localeUS, "1,000e1_0", "0", ErrSyntax},
+	{localeUS, "0x1_0p0", "16", nil},
+
+	{localeIN, "12,34,567.8", "1.2345678e+06", nil},
+	{localeIN, "1,23,45,67,890", "1.23456789e+09", nil},
+	{localeIN, "999", "999", nil},
+	{localeIN, "1,000", "1000", nil},
+	{localeIN, "1,234,567.8", "0", ErrSyntax},
+	{localeIN, "123,45,678", "0", ErrSyntax},
+	{localeIN, "12,345", "12345", nil},
+	{localeIN, "1,2345", "0", ErrSyntax},
+
+	// a decimal mark alone, Go underscores still work.
+	{Locale{Point: ','}, "1_000,5", "1000.5", nil},
+	{Locale{Point: ','}, "1.5", "0", ErrSyntax},
+}
+
+func TestLocale(t *testing.T) {
+	for _, test := range localetests {
+		par := Parser{Locale: test.loc}
+		out, err := par.ParseFloat(test.in, 64)
+		outs := strconv.FormatFloat(out, 'g', -1, 64)
+		if outs != test.out || !errors.Is(err, test.err) {
+			t.Errorf("%+v.ParseFloat(%q, 64) = %v, %v want %v, %v",
+				par, test.in, out, err, test.out, test.err)
+		}
+		out32, err := par.ParseFloat32(test.in)
+		want, _ := strconv.ParseFloat(test.out, 32)
+		if float32(want) != out32 || !errors.Is(err, test.err) {
+			t.Errorf("%+v.ParseFloat32(%q) = %v, %v want %v, %v",
+				par, test.in, out32, err, float32(want), test.err)
+		}
+	}
+}
+
+// localize rewrites a number formatted by strconv in the locale.
+func localize(num string, loc Locale) string {
+	mant, exp, _ := strings.Cut(num, "e")
+	if exp != "" {
+		exp = "e" + exp
+	}
+	sign := ""
+	if strings.HasPrefix(mant, "-") {
+		sign, mant = "-", mant[1:]
+	}
+	ipart, fpart, point := strings.Cut(mant, ".")
+	var groups []string
+	size := 3
+	for len(ipart) > size {
+		groups = append([]string{ipart[len(ipart)-size:]}, groups...)
+		ipart = ipart[:len(ipart)-size]
+		if loc.Indian {
+			size = 2
+		}
+	}
+	groups = append([]string{ipart}, groups...)
+	mant = strings.Join(groups, string(loc.Group))
+	if point {
+		mant += string(loc.Point) + fpart
+	}
+	return sign + mant + exp
+}
+
+func TestLocaleRandom(t *testing.T) {
+	initAtof()
+	for _, loc := range []Locale{localeDE, localeFR, localeUS, localeIN} {
+		par := Parser{Locale: loc}
+		for _, test := range atofRandomTests {
+			num := strconv.FormatFloat(test.x, 'f', -1, 64)
+			if len(num) > 400 {
+				num = test.s
+			}
+			inp := localize(num, loc)
+			out, err := par.ParseFloat(inp, 64)
+			if !isFloat64(out, test.x) || err != nil {
+				t.Errorf("%+v.ParseFloat(%q, 64) = %v, %v want %v, nil", par, inp, out, err, test.x)
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package refloat
+
+// nanParseFloat reads the NaN at num[offset:], which is "nan" or "snan"
+// optionally followed by a payload in parentheses, as in C99.
+// sign is the sign already read by the caller.
+func nanParseFloat[T text](num T, offset int, sign int, p *Parser, f format) (uint64, int, error) {
+	const fnc = "ParseFloat"
+	width, prec := f.width, f.prec
+	// the highest bit of the mantissa tells quiet NaNs from signaling ones.
+	// the rest is the payload.
+	quiet := uint64(1) << (prec - 1)
+
+	signal, load, over, offset, err := nanRead(num, offset, p)
+	if err != nil {
+		return 0, 0, err
+	}
+	if load >= quiet {
+		over = true
+	}
+	load &= quiet - 1
+	if signal && load == 0 {
+		// zero would be Inf.
+		load = 1
+	}
+	if !signal {
+		load |= quiet
+	}
+	bit := uint64(1)<<(width-prec-1) - 1
+	bit = bit<<prec | load | uint64(sign)<<(width-1)
+	if over {
+		return bit, offset, errorRange(fnc, num)
+	}
+	return bit, offset, nil
+}
+
+// nanRead reads the NaN at num[offset:] for nanParseFloat. it returns whether
+// it's signaling, the payload, whether the payload overflowed 64 bits, and
+// where the NaN ends.
+func nanRead[T text](num T, offset int, p *Parser) (bool, uint64, bool, int, error) {
+	const fnc = "ParseFloat"
+	begin := offset
+	var signal bool
+	if num[offset]|0x20 == 's' && (!p.CaseSensitive || num[offset] == 's') {
+		signal = true
+		offset++
+	}
+	if common(num[offset:], "NaN", p.CaseSensitive) != 3 {
+		return false, 0, false, 0, errorSyntax(fnc, num, begin, ErrMantissa)
+	}
+	offset += 3
+
+	var load uint64
+	var over bool
+	// like strtod, the payload is only read when the parentheses are closed
+	// around the n-char-sequence; if not, "nan" is read alone.
+	end := offset + 1
+	for ; end < len(num); end++ {
+		char := num[end]
+		if char-'0' > '9'-'0' && char|0x20-'a' > 'z'-'a' && char != '_' {
+			break
+		}
+	}
+	if offset < len(num) && num[offset] == '(' && end < len(num) && num[end] == ')' {
+		var ok bool
+		load, over, ok = payload(num[offset+1 : end])
+		if !ok {
+			return false, 0, false, 0, errorSyntax(fnc, num, offset+1, ErrPayload)
+		}
+		offset = end + 1
+	}
+	return signal, load, over, offset, nil
+}
+
+// payload reads seq as strtoull does with base 0: hexadecimal with "0x",
+// octal with a leading '0', and decimal otherwise. it reports whether it
+// overflowed, and whether seq was a number at all.
+func payload[T text](seq T) (uint64, bool, bool) {
+	base := uint64(10)
+	if len(seq) > 1 && seq[0] == '0' && seq[1]|0x20 == 'x' {
+		base = 16
+		seq = seq[2:]
+		if len(seq) == 0 {
+			return 0, false, false
+		}
+	} else if len(seq) > 0 && seq[0] == '0' {
+		base = 8
+	}
+	var load uint64
+	var over bool
+	for idx := 0; idx < len(seq); idx++ {
+		char := seq[idx]
+		var dig uint64
+		switch {
+		case char-'0' <= '9'-'0':
+			dig = uint64(char - '0')
+		case char|0x20-'a' <= 'f'-'a':
+			dig = uint64(char|0x20-'a') + 10
+		default:
+			return 0, false, false
+		}
+		if dig >= base {
+			return 0, false, false
+		}
+		if load > (1<<64-1-dig)/base {
+			over = true
+		}
+		load = load*base + dig
+	}
+	return load, over, true
+}
@@ -0,0 +1,74 @@
+package refloat_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+type nanTest struct {
+	inp    string
+	bits64 uint64
+	bits32 uint32
+	err    error
+}
+
+var nantests = []nanTest{
+	{"nan", 0x7ff8000000000000, 0x7fc00000, nil},
+	{"NaN", 0x7ff8000000000000, 0x7fc00000, nil},
+	{"+nan", 0x7ff8000000000000, 0x7fc00000, nil},
+	{"-nan", 0xfff8000000000000, 0xffc00000, nil},
+	{"snan", 0x7ff0000000000001, 0x7f800001, nil},
+	{"-sNaN", 0xfff0000000000001, 0xff800001, nil},
+	{"nan()", 0x7ff8000000000000, 0x7fc00000, nil},
+	{"nan(0x8000f)", 0x7ff800000008000f, 0x7fc8000f, nil},
+	{"nan(123)", 0x7ff800000000007b, 0x7fc0007b, nil},
+	{"nan(0173)", 0x7ff800000000007b, 0x7fc0007b, nil},
+	{"-nan(0X1F)", 0xfff800000000001f, 0xffc0001f, nil},
+	{"snan(5)", 0x7ff0000000000005, 0x7f800005, nil},
+	{"snan(0)", 0x7ff0000000000001, 0x7f800001, nil},
+	{"nan(4194303)", 0x7ff80000003fffff, 0x7fffffff, nil},
+	{"nan(0xfffffffffffff)", 0x7fffffffffffffff, 0x7fffffff, ErrRange},
+	{"nan(0x8000000000000)", 0x7ff8000000000000, 0x7fc00000, ErrRange},
+	{"nan(99999999999999999999999)", 0, 0, ErrRange},
+	{"nan(0x)", 0, 0, ErrPayload},
+	{"nan(09)", 0, 0, ErrPayload},
+	{"nan(abc)", 0, 0, ErrPayload},
+	{"nan(1", 0, 0, ErrTrailing},
+	{"nan(1 )", 0, 0, ErrTrailing},
+	{"nanx", 0, 0, ErrTrailing},
+	{"sna", 0, 0, ErrMantissa},
+	{"-s", 0, 0, ErrMantissa},
+}
+
+func TestNaNPayloads(t *testing.T) {
+	par := Parser{NaNPayloads: true}
+	for _, test := range nantests {
+		f64, err := par.ParseFloat64(test.inp)
+		if !errors.Is(err, test.err) || (err == nil) != (test.err == nil) || test.bits64 != 0 && math.Float64bits(f64) != test.bits64 {
+			t.Errorf("%+v.ParseFloat64(%q) = %#x, %v want %#x, %v", par, test.inp, math.Float64bits(f64), err, test.bits64, test.err)
+		}
+		f32, err := par.ParseFloat32(test.inp)
+		if !errors.Is(err, test.err) || (err == nil) != (test.err == nil) || test.bits32 != 0 && math.Float32bits(f32) != test.bits32 {
+			t.Errorf("%+v.ParseFloat32(%q) = %#x, %v want %#x, %v", par, test.inp, math.Float32bits(f32), err, test.bits32, test.err)
+		}
+	}
+}
+
+func TestNaNPayloadsOff(t *testing.T) {
+	for _, inp := range []string{"-nan", "snan", "nan(1)"} {
+		if _, err := ParseFloat(inp, 64); !errors.Is(err, ErrSyntax) {
+			t.Errorf("ParseFloat(%q, 64) = _, %v want %v", inp, err, ErrSyntax)
+		}
+	}
+	par := Parser{NaNPayloads: true, CaseSensitive: true}
+	if _, err := par.ParseFloat("SNaN", 64); !errors.Is(err, ErrSyntax) {
+		t.Errorf("%+v.ParseFloat(%q, 64) = _, %v want %v", par, "SNaN", err, ErrSyntax)
+	}
+	par = Parser{NaNPayloads: true, NoSpecials: true}
+	if _, err := par.ParseFloat("nan(1)", 64); !errors.Is(err, ErrSyntax) {
+		t.Errorf("%+v.ParseFloat(%q, 64) = _, %v want %v", par, "nan(1)", err, ErrSyntax)
+	}
+}
@@ -0,0 +1,46 @@
+package refloat
+
+import "math/big"
+
+// An Overflow is a policy for results that are too large
+// in magnitude for the size.
+type Overflow int
+
+const (
+	// OverflowInf returns ±Inf and ErrRange. If the rounding mode
+	// rounds toward zero in that direction, it returns the largest
+	// finite number and ErrRange instead.
+	OverflowInf Overflow = iota
+	// OverflowSaturate returns the largest finite number of the sign and ErrRange.
+	OverflowSaturate
+	// OverflowSaturateSilent returns the largest finite number of the sign
+	// without an error, unless Parser.Exact asks for ErrInexact.
+	OverflowSaturateSilent
+)
+
+// toInf reports whether a result that overflows with the sign becomes Inf
+// with the policy and the rounding mode of p. if not, it's the largest
+// finite number instead.
+func (p *Parser) toInf(sign int) bool {
+	if p.Overflow != OverflowInf {
+		return false
+	}
+	switch p.Rounding {
+	case big.ToZero, toOdd:
+		return false
+	case big.ToNegativeInf:
+		return sign != 0
+	case big.ToPositiveInf:
+		return sign == 0
+	}
+	return true
+}
+
+// overflow returns the error for a result that overflows,
+// which is inexact too, see check.
+func (p *Parser) overflow() error {
+	if p.Overflow != OverflowSaturateSilent {
+		return ErrRange
+	}
+	return nil
+}
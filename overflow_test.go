@@ -0,0 +1,97 @@
+package refloat_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+type overflowTest struct {
+	inp  string
+	size int
+	out  float64 // the result of saturating ones.
+}
+
+var overflowtests = []overflowTest{
+	{"1e400", 64, math.MaxFloat64},
+	{"-1e400", 64, -math.MaxFloat64},
+	{"1.7976931348623159e308", 64, math.MaxFloat64},
+	{"0x1p1024", 64, math.MaxFloat64},
+	{"-0x1.fffffffffffff8p1023", 64, -math.MaxFloat64},
+	{"1e39", 32, math.MaxFloat32},
+	{"-3.4028236e38", 32, -math.MaxFloat32},
+	// these only overflow after rounding.
+	{"3.40282357e38", 32, math.MaxFloat32},
+	{"0x1.ffffffp127", 32, math.MaxFloat32},
+	{"-0x1.ffffffp127", 32, -math.MaxFloat32},
+	{"179769313486231580793728971405303415079934132710037826936173778980444968292764750946649017977587207096330286416692887910946555547851940402630657488671505820681908902000708383676273854845817711531764475730270069855571366959622842914819860834936475292719074168444365510704342711559699508093042880177904174497792", 64, math.MaxFloat64},
+}
+
+func TestOverflow(t *testing.T) {
+	for _, test := range overflowtests {
+		for _, pol := range []Overflow{OverflowInf, OverflowSaturate, OverflowSaturateSilent} {
+			par := Parser{Overflow: pol}
+			want, werr := test.out, error(nil)
+			switch pol {
+			case OverflowInf:
+				want, werr = math.Inf(int(math.Copysign(1, want))), ErrRange
+			case OverflowSaturate:
+				werr = ErrRange
+			}
+			out, err := par.ParseFloat(test.inp, test.size)
+			if out != want || !errors.Is(err, werr) || (err == nil) != (werr == nil) {
+				t.Errorf("Parser{Overflow: %d}.ParseFloat(%q, %d) = %v, %v want %v, %v", pol, test.inp, test.size, out, err, want, werr)
+			}
+		}
+	}
+}
+
+func TestOverflowNotOverflow(t *testing.T) {
+	for _, test := range []struct {
+		inp  string
+		size int
+	}{
+		{"Inf", 32}, {"-Infinity", 64}, {"1.7976931348623157e308", 64}, {"3.4028234e38", 32},
+		{"0x1.fffffep127", 32}, {"0x1.fffffffffffff7p1023", 64},
+	} {
+		par := Parser{Overflow: OverflowSaturate}
+		want, _ := ParseFloat(test.inp, test.size)
+		out, err := par.ParseFloat(test.inp, test.size)
+		if out != want || err != nil {
+			t.Errorf("Parser{Overflow: OverflowSaturate}.ParseFloat(%q, %d) = %v, %v want %v, <nil>", test.inp, test.size, out, err, want)
+		}
+	}
+	par := Parser{Overflow: OverflowSaturateSilent, Exact: true}
+	out, err := par.ParseFloat("1e400", 64)
+	if out != math.MaxFloat64 || !errors.Is(err, ErrInexact) {
+		t.Errorf("%+v.ParseFloat(%q, 64) = %v, %v want %v, %v", par, "1e400", out, err, math.MaxFloat64, ErrInexact)
+	}
+}
+
+func TestOverflowRandom(t *testing.T) {
+	for _, inp := range roundingInputs() {
+		rat, ok := new(big.Rat).SetString(inp)
+		if !ok {
+			t.Fatalf("bad test input %q", inp)
+		}
+		for _, mode := range roundingModes {
+			for _, size := range []int{32, 64} {
+				want, over := roundRat(rat, size, mode)
+				if over {
+					want = math.Copysign(math.MaxFloat64, want)
+					if size == 32 {
+						want = math.Copysign(math.MaxFloat32, want)
+					}
+				}
+				par := Parser{Rounding: mode, Overflow: OverflowSaturateSilent}
+				out, err := par.ParseFloat(inp, size)
+				if math.Float64bits(out) != math.Float64bits(want) || err != nil {
+					t.Errorf("%+v.ParseFloat(%q, %d) = %v, %v want %v, <nil>", par, inp, size, out, err, want)
+				}
+			}
+		}
+	}
+}
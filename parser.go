@@ -0,0 +1,101 @@
+package refloat
+
+import "math/big"
+
+type (
+	// A Parser converts strings to floating-point numbers like ParseFloat,
+	// but lets you restrict the accepted syntax.
+	// The zero value accepts the same syntax as ParseFloat; each field
+	// turns off (or tightens) one part of it. A Parser is safe for
+	// concurrent use as long as it's not modified.
+	Parser struct {
+		NoUnderscores   bool // reject '_' between digits, such as "1_000".
+		NoHex           bool // reject hexadecimal mantissas, such as "0x1p-2".
+		NoSpecials      bool // reject "Inf", "Infinity" and "NaN".
+		CaseSensitive   bool // accept the special values only when spelled exactly as above.
+		NoLeadingPlus   bool // reject '+' in front of the number, such as "+1". exponents still take it.
+		NoLeadingPoint  bool // reject a '.' with no digit before it, such as ".5".
+		NoTrailingPoint bool // reject a '.' with no digit after it, such as "5.".
+		NoLeadingZeros  bool // reject a decimal integer part with a leading zero, such as "01".
+
+		// NaNPayloads accepts NaNs as C does, such as "-nan", "snan" and
+		// "nan(0x8000f)", and returns their exact bit patterns.
+		// A sign sets the sign bit, "snan" is signaling and the others are
+		// quiet. The payload is the mantissa without the quiet bit; it is
+		// read as strtoull does with base 0, and is ErrRange if it doesn't
+		// fit. Signaling NaNs without a payload have 1 to not become Inf.
+		// Plain "nan" is the quiet NaN with no payload.
+		//
+		// Note that ParseFloat with size=32 converts float32 to float64,
+		// which quiets signaling NaNs; use ParseFloat32 to keep them.
+		NaNPayloads bool
+
+		// Specials are extra spellings of Inf and NaN, such as AltSpecials().
+		// They are checked after the sign, before anything else, even with
+		// NoSpecials; the longest one that matches is used. Both Inf and
+		// NaN take the sign, and the NaN is quiet with no payload.
+		Specials []Special
+
+		// Locale is the notation of decimal mantissas.
+		// The zero value is the Go notation.
+		Locale Locale
+
+		// Rounding is the rounding mode of inexact results.
+		// The zero value is big.ToNearestEven, which is what ParseFloat uses.
+		// When a result overflows, it is Inf if the mode rounds away from zero
+		// in that direction and the largest finite number otherwise; the error
+		// is ErrRange either way. See also Overflow.
+		Rounding big.RoundingMode
+
+		// Overflow is the policy for results that overflow.
+		// The zero value is OverflowInf, which is what ParseFloat does.
+		Overflow Overflow
+
+		// Exact makes results that had to be rounded an error, ErrInexact.
+		// The rounded result is returned along with it.
+		Exact bool
+
+		// Underflow makes results that are subnormal or zero, even though
+		// the input is not zero, an error, ErrUnderflow. The result is
+		// returned along with it. It takes precedence over Exact.
+		Underflow bool
+
+		// FlushToZero makes results that would be subnormal zeros of the
+		// same sign. A result is subnormal if it is after rounding.
+		FlushToZero bool
+	}
+)
+
+// std is the Parser behind the package-level functions.
+var std Parser
+
+// ParseFloat is like the package-level ParseFloat, but with the syntax of p.
+func (p *Parser) ParseFloat(num string, size int) (float64, error) {
+	return parseFloatAll(num, size, p)
+}
+
+// ParseFloatPrefix is like the package-level ParseFloatPrefix, but with the syntax of p.
+func (p *Parser) ParseFloatPrefix(num string, size int) (float64, int, error) {
+	return parseFloat(num, size, p)
+}
+
+// ParseFloatBytes is like the package-level ParseFloatBytes, but with the syntax of p.
+func (p *Parser) ParseFloatBytes(num []byte, size int) (float64, error) {
+	return parseFloatAll(num, size, p)
+}
+
+// ParseFloatPrefixBytes is like the package-level ParseFloatPrefixBytes, but with the syntax of p.
+func (p *Parser) ParseFloatPrefixBytes(num []byte, size int) (float64, int, error) {
+	return parseFloat(num, size, p)
+}
+
+// ParseFloat32 is like the package-level ParseFloat32, but with the syntax of p.
+func (p *Parser) ParseFloat32(num string) (float32, error) {
+	f32, read, err := parseFloat32(num, p)
+	return whole(num, f32, read, err, "ParseFloat")
+}
+
+// ParseFloat64 is like the package-level ParseFloat64, but with the syntax of p.
+func (p *Parser) ParseFloat64(num string) (float64, error) {
+	return parseFloatAll(num, 64, p)
+}
@@ -0,0 +1,105 @@
+package refloat_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+type parserTest struct {
+	par Parser
+	in  string
+	out string
+	err error
+}
+
+var parsertests = []parserTest{
+	{Parser{}, "1_000.5", "1000.5", nil},
+	{Parser{NoUnderscores: true}, "1_000.5", "0", ErrSyntax},
+	{Parser{NoUnderscores: true}, "1e1_0", "0", ErrSyntax},
+	{Parser{NoUnderscores: true}, "0x1_0p0", "0", ErrSyntax},
+	{Parser{NoUnderscores: true}, "1000.5", "1000.5", nil},
+
+	{Parser{}, "0x1p-2", "0.25", nil},
+	{Parser{NoHex: true}, "0x1p-2", "0", ErrSyntax},
+	{Parser{NoHex: true}, "0", "0", nil},
+
+	{Parser{NoSpecials: true}, "Inf", "0", ErrSyntax},
+	{Parser{NoSpecials: true}, "-infinity", "0", ErrSyntax},
+	{Parser{NoSpecials: true}, "NaN", "0", ErrSyntax},
+	{Parser{CaseSensitive: true}, "Inf", "+Inf", nil},
+	{Parser{CaseSensitive: true}, "-Infinity", "-Inf", nil},
+	{Parser{CaseSensitive: true}, "NaN", "NaN", nil},
+	{Parser{CaseSensitive: true}, "inf", "0", ErrSyntax},
+	{Parser{CaseSensitive: true}, "INFINITY", "0", ErrSyntax},
+	{Parser{CaseSensitive: true}, "nan", "0", ErrSyntax},
+	{Parser{CaseSensitive: true}, "NAN", "0", ErrSyntax},
+
+	{Parser{NoLeadingPlus: true}, "+1", "0", ErrSyntax},
+	{Parser{NoLeadingPlus: true}, "+Inf", "0", ErrSyntax},
+	{Parser{NoLeadingPlus: true}, "-1", "-1", nil},
+	{Parser{NoLeadingPlus: true}, "1e+1", "10", nil},
+
+	{Parser{NoLeadingPoint: true}, ".5", "0", ErrSyntax},
+	{Parser{NoLeadingPoint: true}, "-.5e1", "0", ErrSyntax},
+	{Parser{NoLeadingPoint: true}, "0x.8p1", "0", ErrSyntax},
+	{Parser{NoLeadingPoint: true}, "0.5", "0.5", nil},
+	{Parser{NoTrailingPoint: true}, "5.", "0", ErrSyntax},
+	{Parser{NoTrailingPoint: true}, "5.e1", "0", ErrSyntax},
+	{Parser{NoTrailingPoint: true}, "0x1.p1", "0", ErrSyntax},
+	{Parser{NoTrailingPoint: true}, "5.0", "5", nil},
+
+	// these go through the slow path, which must stop where the fast path did.
+	{Parser{}, "1.00000000000000011102230246251565404236316680908203125_1", "1.0000000000000002", nil},
+	{Parser{NoUnderscores: true}, "1.00000000000000011102230246251565404236316680908203125_1", "0", ErrSyntax},
+	{Parser{NoUnderscores: true}, "1.00000000000000011102230246251565404236316680908203125", "1", nil},
+	{Parser{NoTrailingPoint: true}, "1" + strings.Repeat("0", 30) + ".", "0", ErrSyntax},
+}
+
+func TestParser(t *testing.T) {
+	for _, test := range parsertests {
+		out, err := test.par.ParseFloat(test.in, 64)
+		outs := strconv.FormatFloat(out, 'g', -1, 64)
+		if outs != test.out || !errors.Is(err, test.err) {
+			t.Errorf("%+v.ParseFloat(%v, 64) = %v, %v want %v, %v",
+				test.par, test.in, out, err, test.out, test.err)
+		}
+		out32, err := test.par.ParseFloat32(test.in)
+		outs = strconv.FormatFloat(float64(out32), 'g', -1, 64)
+		if float64(float32(out)) == out && (outs != test.out || !errors.Is(err, test.err)) {
+			t.Errorf("%+v.ParseFloat32(%v) = %v, %v want %v, %v",
+				test.par, test.in, out32, err, test.out, test.err)
+		}
+	}
+}
+
+func TestParserPrefix(t *testing.T) {
+	par := Parser{NoUnderscores: true}
+	const num = "1.00000000000000011102230246251565404236316680908203125"
+	out, read, err := par.ParseFloatPrefix(num+"_1", 64)
+	if out != 1 || read != len(num) || err != nil {
+		t.Errorf("%+v.ParseFloatPrefix(%v, 64) = %v, %v, %v want 1, %v, nil",
+			par, num+"_1", out, read, err, len(num))
+	}
+	out, read, err = par.ParseFloatPrefixBytes([]byte(num+"_1"), 64)
+	if out != 1 || read != len(num) || err != nil {
+		t.Errorf("%+v.ParseFloatPrefixBytes(%v, 64) = %v, %v, %v want 1, %v, nil",
+			par, num+"_1", out, read, err, len(num))
+	}
+}
+
+// the zero Parser must behave exactly like the package-level functions.
+func TestParserZero(t *testing.T) {
+	var par Parser
+	for _, test := range atoftests {
+		out, err := par.ParseFloat(test.in, 64)
+		outs := strconv.FormatFloat(out, 'g', -1, 64)
+		if outs != test.out || !errors.Is(err, test.err) {
+			t.Errorf("Parser{}.ParseFloat(%v, 64) = %v, %v want %v, %v",
+				test.in, out, err, test.out, test.err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package refloat
+
+import "math/big"
+
+const (
+	// the limit of the decimal exponent for ParseRat, after the fraction
+	// digits are counted. 10^(1<<20) is about 400KiB.
+	ratLimit = 1 << 20
+)
+
+// ParseRat converts num to its exact value as a *big.Rat. num has the same
+// syntax as for ParseFloat, and the result is never rounded.
+//
+// Infs and NaNs, which big.Rat can't hold, are reported as ErrRange with a nil
+// result. So are exponents beyond ±2^20, or ±2^22 for hexadecimals, since the
+// result would take too much memory. The sign of zeros is dropped.
+func ParseRat(num string) (*big.Rat, error) {
+	return std.ParseRat(num)
+}
+
+// ParseRat is like the package-level ParseRat, but with the syntax of p.
+func (p *Parser) ParseRat(num string) (*big.Rat, error) {
+	const fnc = "ParseRat"
+	// at most len(num) digits are dropped after mant, so the exponents
+	// of the last digit up to the limit are kept exactly.
+	dec, done, _, read, err := scan64(num, p, ratLimit+len(num))
+	_, err = whole(num, 0, read, err, fnc)
+	if err != nil && err.(*NumError).Err == ErrSyntax {
+		return nil, err
+	}
+	if done && !dec.hex {
+		return nil, errorRange(fnc, num)
+	}
+
+	mant, exp, hex, sign := new(big.Int), dec.exp10, dec.hex, dec.sign
+	// the exponent of the last digit, which the limit is for.
+	last := exp - dec.drop
+	if hex || dec.trunc {
+		// bigRead keeps the exponents up to the limit exactly.
+		mant, exp, hex, sign = bigRead(num, p, ratLimit)
+		last = exp
+	} else {
+		// dropped zeros are in exp.
+		mant.SetUint64(dec.mant)
+	}
+	if mant.Sign() == 0 {
+		return new(big.Rat), nil
+	}
+	if sign != 0 {
+		mant.Neg(mant)
+	}
+	limit, base := ratLimit, big.NewInt(10)
+	if hex {
+		limit, base = ratLimit*4, big.NewInt(2)
+	}
+	if last > limit || last < -limit {
+		return nil, errorRange(fnc, num)
+	}
+	pow := new(big.Int).Exp(base, big.NewInt(int64(max(exp, -exp))), nil)
+	if exp >= 0 {
+		return new(big.Rat).SetInt(mant.Mul(mant, pow)), nil
+	}
+	return new(big.Rat).SetFrac(mant, pow), nil
+}
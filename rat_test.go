@@ -0,0 +1,75 @@
+package refloat_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+func TestParseRat(t *testing.T) {
+	for _, test := range []struct {
+		inp string
+		out string
+		err error
+	}{
+		{"1", "1/1", nil},
+		{"-0.1", "-1/10", nil},
+		{"+2.50", "5/2", nil},
+		{"1_000e-3", "1/1", nil},
+		{"1e20", "100000000000000000000/1", nil},
+		{"123456789012345678901234567890", "123456789012345678901234567890/1", nil},
+		{"-0", "0/1", nil},
+		{"0e99999999999", "0/1", nil},
+		{"0x1.8p-1", "3/4", nil},
+		{"0x_ff.8p1", "511/1", nil},
+		{"-0X1P+10", "-1024/1", nil},
+		// far beyond float64, but not the limit.
+		{"1e-400", "1/1" + strings.Repeat("0", 400), nil},
+		{"1e1048577", "", ErrRange},
+		{"1e-99999999999", "", ErrRange},
+		{"0x1p4194305", "", ErrRange},
+		{"inf", "", ErrRange},
+		{"nan", "", ErrRange},
+		{"1e", "", ErrSyntax},
+		{"1x", "", ErrSyntax},
+	} {
+		out, err := ParseRat(test.inp)
+		var str string
+		if out != nil {
+			str = out.String()
+		}
+		if str != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("ParseRat(%q) = %s, %v want %s, %v", test.inp, str, err, test.out, test.err)
+		}
+	}
+	_, err := ParseRat("1.5e")
+	var num *NumError
+	if !errors.As(err, &num) || num.Func != "ParseRat" || !errors.Is(err, ErrExponent) {
+		t.Errorf("ParseRat(%q) = _, %#v; want a *NumError for ParseRat", "1.5e", err)
+	}
+}
+
+func TestParseRatParser(t *testing.T) {
+	for _, test := range []struct {
+		par Parser
+		inp string
+		out string
+		err error
+	}{
+		{Parser{NoHex: true}, "0x1p0", "", ErrSyntax},
+		{Parser{NoUnderscores: true}, "1_0", "", ErrSyntax},
+		{Parser{Locale: Locale{Point: ',', Group: '.'}}, "1.000,25", "4001/4", nil},
+		{Parser{Exact: true}, "0.1", "1/10", nil},
+	} {
+		out, err := test.par.ParseRat(test.inp)
+		var str string
+		if out != nil {
+			str = out.String()
+		}
+		if str != test.out || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("%+v.ParseRat(%q) = %s, %v want %s, %v", test.par, test.inp, str, err, test.out, test.err)
+		}
+	}
+}
@@ -1,5 +1,16 @@
 package refloat
 
+import (
+	"math/big"
+	"unsafe"
+)
+
+// text is the input the parser accepts. both are read in place,
+// so byte slices don't have to be converted to strings first.
+type text interface {
+	string | []byte
+}
+
 // ParseFloat converts the string num to a floating-point number
 // with the precision specified by size: 32 for float32, or 64 for float64.
 // When size=32, the result still has type float64, but it will be
@@ -17,7 +28,8 @@ package refloat
 // The errors that ParseFloat returns have concrete type *NumError
 // and include err.Num = num.
 //
-// If num is not syntactically well-formed, ParseFloat returns err.Err = ErrSyntax.
+// If num is not syntactically well-formed, ParseFloat returns err.Err = ErrSyntax,
+// with err.Offset and err.Reason telling where and what the problem is.
 //
 // If num is syntactically well-formed but is more than 1/2 ULP
 // away from the largest floating point number of the given size,
@@ -28,18 +40,101 @@ package refloat
 //
 // [floating-point literals]: https://go.dev/ref/spec#Floating-point_literals
 func ParseFloat(num string, size int) (float64, error) {
-	const fnc = "ParseFloat"
-	f64, read, err := parseFloat(num, size)
+	return parseFloatAll(num, size, &std)
+}
+
+// ParseFloatBytes is like ParseFloat but takes a byte slice.
+// It reads num in place without converting it to a string;
+// num is only copied into err.Num when it returns an error.
+func ParseFloatBytes(num []byte, size int) (float64, error) {
+	return parseFloatAll(num, size, &std)
+}
+
+// ParseFloatPrefix is like ParseFloat but parses the longest prefix
+// of num that is a valid floating-point number, and also returns
+// the number of bytes it consumed.
+//
+// On syntax errors the returned length is 0. On ErrRange, it is
+// the length of the prefix that was out of range.
+func ParseFloatPrefix(num string, size int) (float64, int, error) {
+	return parseFloat(num, size, &std)
+}
+
+// ParseFloatPrefixBytes is like ParseFloatPrefix but takes a byte slice.
+// Like ParseFloatBytes, it only allocates for errors.
+func ParseFloatPrefixBytes(num []byte, size int) (float64, int, error) {
+	return parseFloat(num, size, &std)
+}
+
+// ParseFloat32 is like ParseFloat with size=32, but it returns
+// the float32 directly instead of widening it to float64.
+func ParseFloat32(num string) (float32, error) {
+	f32, read, err := parseFloat32(num, &std)
+	return whole(num, f32, read, err, "ParseFloat")
+}
+
+// ParseFloat64 is like ParseFloat with size=64.
+func ParseFloat64(num string) (float64, error) {
+	return parseFloatAll(num, 64, &std)
+}
+
+// Parse is like ParseFloat, but the precision is the one of T.
+// Types whose underlying type is float32 are parsed as ParseFloat32,
+// and the others as ParseFloat64.
+func Parse[T ~float32 | ~float64](num string) (T, error) {
+	var zero T
+	// this is a constant for each instantiation, the
+	// branch not taken doesn't make it into the binary.
+	if unsafe.Sizeof(zero) == 4 {
+		f32, err := ParseFloat32(num)
+		return T(f32), err
+	}
+	f64, err := ParseFloat64(num)
+	return T(f64), err
+}
+
+func parseFloatAll[T text](num T, size int, p *Parser) (float64, error) {
+	f64, read, err := parseFloat(num, size, p)
+	return whole(num, f64, read, err, "ParseFloat")
+}
+
+// parseAll parses all of num to float64 rounded in mode, with the syntax of p
+// but none of its other options, for the parsers that round the result again.
+// the errors are of fnc.
+func parseAll[T text](num T, fnc string, p *Parser, mode big.RoundingMode) (float64, error) {
+	with := p.with(mode)
+	f64, read, err := parseFloat64(num, &with)
+	return whole(num, f64, read, err, fnc)
+}
+
+// whole returns the result of a parser of a prefix of num as the result of
+// all of num, where read is the length of the prefix. the rest of num is
+// ErrTrailing, and the errors are of fnc. float32 is returned as is, since
+// widening it would change the bits of signaling NaNs.
+func whole[T text, F any](num T, f F, read int, err error, fnc string) (F, error) {
 	if read != len(num) && (err == nil || err.(*NumError).Err != ErrSyntax) {
-		return 0, errorSyntax(fnc, num)
+		var zero F
+		return zero, errorSyntax(fnc, num, read, ErrTrailing)
 	}
-	return f64, err
+	if err != nil {
+		err.(*NumError).Func = fnc
+	}
+	return f, err
 }
 
-func parseFloat(num string, size int) (float64, int, error) {
+func parseFloat[T text](num T, size int, p *Parser) (float64, int, error) {
 	if size == 32 {
-		f32, read, err := parseFloat32(num)
+		f32, read, err := parseFloat32(num, p)
 		return float64(f32), read, err
 	}
-	return parseFloat64(num)
+	return parseFloat64(num, p)
+}
+
+// readFloat is parseFloat like read64.
+func readFloat[T text](num T, size int, p *Parser) (float64, int, bool, error) {
+	if size == 32 {
+		f32, read, inexact, err := read32(num, p)
+		return float64(f32), read, inexact, err
+	}
+	return read64(num, p)
 }
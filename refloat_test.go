@@ -493,29 +493,27 @@ func initAtofOnce() {
 	}
 }
 
-// TODO: support this Test function? (this is only used in ASCII-to-Complex, might be unnecessary).
-//
-// func TestParseFloatPrefix(t *testing.T) {
-// 	for i := range atoftests {
-// 		test := &atoftests[i]
-// 		if test.err != nil {
-// 			continue
-// 		}
-// 		// Adding characters that do not extend a number should not invalidate it.
-// 		// Test a few. The "i" and "init" cases test that we accept "infi", "infinit"
-// 		// correctly as "inf" with suffix.
-// 		for _, suffix := range []string{" ", "q", "+", "-", "<", "=", ">", "(", ")", "i", "init"} {
-// 			in := test.in + suffix
-// 			_, n, err := ParseFloatPrefix(in, 64)
-// 			if err != nil {
-// 				t.Errorf("ParseFloatPrefix(%q, 64): err = %v; want no error", in, err)
-// 			}
-// 			if n != len(test.in) {
-// 				t.Errorf("ParseFloatPrefix(%q, 64): n = %d; want %d", in, n, len(test.in))
-// 			}
-// 		}
-// 	}
-// }
+func TestParseFloatPrefix(t *testing.T) {
+	for i := range atoftests {
+		test := &atoftests[i]
+		if test.err != nil {
+			continue
+		}
+		// Adding characters that do not extend a number should not invalidate it.
+		// Test a few. The "i" and "init" cases test that we accept "infi", "infinit"
+		// correctly as "inf" with suffix.
+		for _, suffix := range []string{" ", "q", "+", "-", "<", "=", ">", "(", ")", "i", "init"} {
+			in := test.in + suffix
+			_, n, err := ParseFloatPrefix(in, 64)
+			if err != nil {
+				t.Errorf("ParseFloatPrefix(%q, 64): err = %v; want no error", in, err)
+			}
+			if n != len(test.in) {
+				t.Errorf("ParseFloatPrefix(%q, 64): n = %d; want %d", in, n, len(test.in))
+			}
+		}
+	}
+}
 
 func testAtof(t *testing.T /*, opt bool*/) {
 	initAtof()
@@ -660,3 +658,93 @@ func TestParseFloatIncorrectBitSize(t *testing.T) {
 		}
 	}
 }
+
+func TestParseFloatBytes(t *testing.T) {
+	for _, test := range atoftests {
+		out, err := ParseFloatBytes([]byte(test.in), 64)
+		outs := strconv.FormatFloat(out, 'g', -1, 64)
+		if outs != test.out || !errors.Is(err, test.err) {
+			t.Errorf("ParseFloatBytes(%v, 64) = %v, %v want %v, %v",
+				test.in, out, err, test.out, test.err)
+		}
+	}
+	for _, test := range atof32tests {
+		out, err := ParseFloatBytes([]byte(test.in), 32)
+		outs := strconv.FormatFloat(out, 'g', -1, 32)
+		if outs != test.out || !errors.Is(err, test.err) {
+			t.Errorf("ParseFloatBytes(%v, 32) = %v, %v want %v, %v",
+				test.in, out, err, test.out, test.err)
+		}
+	}
+
+	// err.Num must not share memory with the input.
+	buf := []byte("1.5x")
+	_, err := ParseFloatBytes(buf, 64)
+	copy(buf, "****")
+	var num *NumError
+	if !errors.As(err, &num) || num.Num != "1.5x" {
+		t.Errorf("ParseFloatBytes(%q, 64) = %v, want an error with Num %q", "1.5x", err, "1.5x")
+	}
+}
+
+// inputs that need the math/big fallback still allocate for the
+// fallback itself, these are not.
+func TestParseFloatBytesAllocs(t *testing.T) {
+	for _, inp := range []string{"1", "-0.1", "1.5e-300", "inf", "NaN", "0x1p-2", "1_000.25"} {
+		buf := []byte(inp)
+		allocs := testing.AllocsPerRun(100, func() {
+			ParseFloatBytes(buf, 64)
+			ParseFloatBytes(buf, 32)
+			ParseFloatPrefixBytes(buf, 64)
+		})
+		if allocs != 0 {
+			t.Errorf("ParseFloatBytes(%q) allocated %v times, want 0", inp, allocs)
+		}
+	}
+}
+
+func TestParseFloat32(t *testing.T) {
+	for _, test := range atof32tests {
+		out, err := ParseFloat32(test.in)
+		outs := strconv.FormatFloat(float64(out), 'g', -1, 32)
+		if outs != test.out || !errors.Is(err, test.err) {
+			t.Errorf("ParseFloat32(%v) = %v, %v want %v, %v",
+				test.in, out, err, test.out, test.err)
+		}
+	}
+}
+
+func TestParseFloat64(t *testing.T) {
+	for _, test := range atoftests {
+		out, err := ParseFloat64(test.in)
+		outs := strconv.FormatFloat(out, 'g', -1, 64)
+		if outs != test.out || !errors.Is(err, test.err) {
+			t.Errorf("ParseFloat64(%v) = %v, %v want %v, %v",
+				test.in, out, err, test.out, test.err)
+		}
+	}
+}
+
+type (
+	celsius float64
+	weight  float32
+)
+
+func TestParse(t *testing.T) {
+	const num = "1.000000059604644775390626"
+	if f32, err := Parse[float32](num); f32 != 1.0000001 || err != nil {
+		t.Errorf("Parse[float32](%q) = %v, %v want 1.0000001, nil", num, f32, err)
+	}
+	if w, err := Parse[weight](num); w != 1.0000001 || err != nil {
+		t.Errorf("Parse[weight](%q) = %v, %v want 1.0000001, nil", num, w, err)
+	}
+	if f64, err := Parse[float64](num); f64 != 1.00000005960464477539 || err != nil {
+		t.Errorf("Parse[float64](%q) = %v, %v want 1.0000000596046448, nil", num, f64, err)
+	}
+	if c, err := Parse[celsius]("-40_0.5x"); c != 0 || !errors.Is(err, ErrSyntax) {
+		t.Errorf("Parse[celsius](%q) = %v, %v want 0, %v", "-40_0.5x", c, err, ErrSyntax)
+	}
+	if c, err := Parse[celsius]("1e400"); !math.IsInf(float64(c), 1) || !errors.Is(err, ErrRange) {
+		t.Errorf("Parse[celsius](%q) = %v, %v want +Inf, %v", "1e400", c, err, ErrRange)
+	}
+}
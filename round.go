@@ -0,0 +1,62 @@
+package refloat
+
+import "math/big"
+
+// toOdd is a rounding mode of our own, which rounds inexact results to the
+// odd one of the neighbors. rounding them again to a format with at least
+// 2 bits less gives the same result as rounding the exact value directly,
+// so it's used to go through float64 to narrower formats.
+const toOdd = big.ToPositiveInf + 1
+
+// with returns a copy of p that rounds in mode and has none of the other
+// options, for the parsers that round the result again with p.
+func (p *Parser) with(mode big.RoundingMode) Parser {
+	with := *p
+	with.Rounding, with.Overflow = mode, OverflowInf
+	with.Exact, with.Underflow, with.FlushToZero = false, false, false
+	return with
+}
+
+// round returns what has to be added to mant>>1 to round it in mode.
+// the lowest bit of mant is the first bit cut off (the "half" bit),
+// and trunc reports whether any bit below it was non-zero.
+func round(mant uint64, trunc bool, sign int, mode big.RoundingMode) uint64 {
+	half := mant & 1
+	var rest uint64
+	if trunc {
+		rest = 1
+	}
+	switch mode {
+	case big.ToNearestAway:
+		return half
+	case big.ToZero:
+		return 0
+	case big.AwayFromZero:
+		return half | rest
+	case big.ToNegativeInf:
+		return (half | rest) & uint64(sign)
+	case big.ToPositiveInf:
+		return (half | rest) &^ uint64(sign)
+	case toOdd:
+		return (half | rest) &^ (mant >> 1)
+	}
+	// ties to even: if all truncated bits are zero, we're at exactly
+	// the middle of 2 floating points. in that case, go to the closest
+	// even number (mant >> 1).
+	return half & (rest | mant>>1)
+}
+
+// check returns err for a result, or ErrInexact if the result is inexact
+// and p asks for it. the other errors come first.
+func (p *Parser) check(inexact bool, err error) error {
+	if err == nil && inexact && p.Exact {
+		return ErrInexact
+	}
+	return err
+}
+
+// nearest reports whether p rounds to nearest even and doesn't have to tell
+// inexact results apart, which is what the hardware does.
+func (p *Parser) nearest() bool {
+	return p.Rounding == big.ToNearestEven && !p.Exact
+}
@@ -0,0 +1,202 @@
+package refloat_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+var roundingModes = []big.RoundingMode{
+	big.ToNearestEven,
+	big.ToNearestAway,
+	big.ToZero,
+	big.AwayFromZero,
+	big.ToNegativeInf,
+	big.ToPositiveInf,
+}
+
+// roundRat rounds rat to a float of the size in mode, and reports whether
+// it overflows. it's the reference implementation for the rounding modes.
+func roundRat(rat *big.Rat, size int, mode big.RoundingMode) (float64, bool) {
+	near, _ := rat.Float64()
+	if size == 32 {
+		f32, _ := rat.Float32()
+		near = float64(f32)
+	}
+	// the exactness Rat reports is not reliable for underflows.
+	if !math.IsInf(near, 0) && new(big.Rat).SetFloat64(near).Cmp(rat) == 0 {
+		return near, false
+	}
+	next := func(f64, dir float64) float64 {
+		if size == 32 {
+			return float64(math.Nextafter32(float32(f64), float32(dir)))
+		}
+		return math.Nextafter(f64, dir)
+	}
+	var lo, hi float64
+	switch {
+	case math.IsInf(near, 1):
+		lo, hi = next(near, 0), near
+	case math.IsInf(near, -1):
+		lo, hi = near, next(near, 0)
+	case new(big.Rat).SetFloat64(near).Cmp(rat) < 0:
+		lo, hi = near, next(near, math.Inf(1))
+	default:
+		lo, hi = next(near, math.Inf(-1)), near
+	}
+	if lo == 0 && hi == 0 {
+		// -0 and 0 are not the neighbors of anything.
+		lo, hi = next(0, math.Inf(-1)), next(0, math.Inf(1))
+	}
+	// the magnitude where the exponent overflows.
+	limit := new(big.Rat).SetFloat64(math.Ldexp(1, 1024-1)) // avoid Inf.
+	limit.Mul(limit, big.NewRat(2, 1))
+	if size == 32 {
+		limit.SetFloat64(math.Ldexp(1, 128))
+	}
+	var out float64
+	switch mode {
+	case big.ToNearestEven:
+		out = near
+	case big.ToNearestAway:
+		out = near
+		if !math.IsInf(lo, 0) && !math.IsInf(hi, 0) {
+			mid := new(big.Rat).Add(new(big.Rat).SetFloat64(lo), new(big.Rat).SetFloat64(hi))
+			mid.Quo(mid, big.NewRat(2, 1))
+			if mid.Cmp(rat) == 0 {
+				out = hi
+				if rat.Sign() < 0 {
+					out = lo
+				}
+			}
+		}
+	case big.ToZero:
+		out = hi
+		if rat.Sign() > 0 {
+			out = lo
+		}
+	case big.AwayFromZero:
+		out = lo
+		if rat.Sign() > 0 {
+			out = hi
+		}
+	case big.ToNegativeInf:
+		out = lo
+	case big.ToPositiveInf:
+		out = hi
+	}
+	if out == 0 {
+		// zero keeps the sign of the input.
+		out = math.Copysign(0, float64(rat.Sign()))
+	}
+	abs := new(big.Rat).Abs(rat)
+	return out, math.IsInf(out, 0) || abs.Cmp(limit) >= 0
+}
+
+// roundingInputs returns numbers that are inexact, ties and near the edges.
+func roundingInputs() []string {
+	inps := []string{
+		"0.1", "-0.1", "1e23", "-1e23", "0.3", "2.5", "-2.5", "1e-320", "-1e-320",
+		"1.7976931348623157e308", "1.7976931348623158e308", "1.797693134862315808e308",
+		"1.7976931348623159e308", "-1.7976931348623159e308", "1e400", "-1e400",
+		"2e-324", "3e-324", "-2e-324", "1e-400", "-1e-400",
+		"3.4028235e38", "3.4028236e38", "-3.4028236e38", "1e-45", "7e-46", "-7e-46",
+		"1.00000000000000011102230246251565404236316680908203125",
+		"-1.00000000000000011102230246251565404236316680908203125",
+		"1.00000000000000011102230246251565404236316680908203124",
+		"1.00000000000000033306690738754696212708950042724609375",
+		"1.000000059604644775390625", "-1.000000059604644775390625",
+		"1090544144181609348671888949248",
+		"0x1.00000000000008p0", "-0x1.00000000000008p0", "0x1.000000000000081p0",
+		"0x1.000001p0", "0x1.0000011p0", "0x1.fffffffffffff8p1023", "-0x1.fffffffffffff8p1023",
+		"0x1.fffffffffffff7fffp1023", "0x.ffffff8p128", "0x0.00000000000008p-1022",
+		"0x0.000000000000081p-1022", "-0x0.00000000000007fp-1022",
+		"0x1.ffffffp-149", "0x1p-150", "0x1.8p-150",
+	}
+	count := 1000
+	if testing.Short() {
+		count = 100
+	}
+	rng := rand.New(rand.NewSource(1))
+	for idx := 0; idx < count; idx++ {
+		// random digits.
+		digs := make([]byte, 1+rng.Intn(25))
+		for idx := range digs {
+			digs[idx] = byte('0' + rng.Intn(10))
+		}
+		digs[0] = byte('1' + rng.Intn(9))
+		inps = append(inps, string(digs)+"e"+strconv.Itoa(rng.Intn(680)-350))
+		// short ones, which go through the hardware.
+		inps = append(inps, strconv.FormatUint(rng.Uint64()>>(11+rng.Intn(50)), 10)+"e"+strconv.Itoa(rng.Intn(45)-22))
+		// exact ties between two float64s and float32s.
+		f64 := math.Ldexp(float64(rng.Uint64()>>11|1), rng.Intn(120)-110)
+		mid := new(big.Rat).SetFloat64(f64)
+		mid.Add(mid, new(big.Rat).SetFloat64(math.Nextafter(f64, math.Inf(1))))
+		mid.Quo(mid, big.NewRat(2, 1))
+		inps = append(inps, "-"+mid.FloatString(200))
+		f32 := float32(f64)
+		mid.SetFloat64(float64(f32))
+		mid.Add(mid, new(big.Rat).SetFloat64(float64(math.Nextafter32(f32, float32(math.Inf(1))))))
+		mid.Quo(mid, big.NewRat(2, 1))
+		inps = append(inps, mid.FloatString(200))
+	}
+	return inps
+}
+
+func TestRounding(t *testing.T) {
+	for _, inp := range roundingInputs() {
+		rat, ok := new(big.Rat).SetString(inp)
+		if !ok {
+			t.Fatalf("bad test input %q", inp)
+		}
+		for _, mode := range roundingModes {
+			par := Parser{Rounding: mode}
+			for _, size := range []int{32, 64} {
+				want, over := roundRat(rat, size, mode)
+				out, err := par.ParseFloat(inp, size)
+				if math.Float64bits(out) != math.Float64bits(want) || over != errors.Is(err, ErrRange) {
+					t.Errorf("Parser{Rounding: %v}.ParseFloat(%q, %d) = %v, %v want %v, overflow: %v",
+						mode, inp, size, out, err, want, over)
+				}
+			}
+		}
+	}
+}
+
+// the default mode goes through the fast paths that use the hardware;
+// the others must agree with it on exact inputs.
+func TestRoundingExact(t *testing.T) {
+	for _, inp := range []string{"0", "-0", "1", "-1", "123456789", "9007199254740992", "0.5", "-0.25e2", "1e22", "0x1.8p1", strings.Repeat("1", 15)} {
+		for _, mode := range roundingModes {
+			par := Parser{Rounding: mode}
+			want, _ := ParseFloat(inp, 64)
+			out, err := par.ParseFloat(inp, 64)
+			if math.Float64bits(out) != math.Float64bits(want) || err != nil {
+				t.Errorf("Parser{Rounding: %v}.ParseFloat(%q, 64) = %v, %v want %v, nil", mode, inp, out, err, want)
+			}
+		}
+	}
+}
+
+// exact inputs, and inexact ones that are short, don't need math/big
+// in any mode.
+func TestRoundingAllocs(t *testing.T) {
+	for _, inp := range []string{"0.5", "1.25", "-7.75", "0.1", "-3.14159", "123e20"} {
+		for _, mode := range roundingModes {
+			par := Parser{Rounding: mode}
+			allocs := testing.AllocsPerRun(100, func() {
+				par.ParseFloat32(inp)
+				par.ParseFloat64(inp)
+			})
+			if allocs != 0 {
+				t.Errorf("Parser{Rounding: %v}.ParseFloat(%q) allocated %v times, want 0", mode, inp, allocs)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package refloat
+
+// A Special is a spelling of Inf or NaN, see Parser.Specials.
+type Special struct {
+	Text string // the spelling without the sign, such as "1.#INF".
+	NaN  bool   // NaN if true, Inf otherwise.
+}
+
+// AltSpecials returns the spellings of Inf and NaN in other runtimes: the
+// ones printed by old versions of the Microsoft C runtime (also found in
+// Excel exports), "∞", and the ones of Scheme. The slice is new on each
+// call, so it can be used as Parser.Specials as is, or appended to:
+//
+//	p := Parser{Specials: append(AltSpecials(), Special{Text: "N/A", NaN: true})}
+func AltSpecials() []Special {
+	return []Special{
+		{Text: "1.#INF"},
+		{Text: "1.#INF00"},
+		{Text: "1.#IND", NaN: true},
+		{Text: "1.#IND00", NaN: true},
+		{Text: "1.#QNAN", NaN: true},
+		{Text: "1.#QNAN0", NaN: true},
+		{Text: "∞"},
+		{Text: "inf.0"},
+		{Text: "nan.0", NaN: true},
+	}
+}
+
+// special returns the longest of p.Specials at the start of num,
+// and its length. the length is 0 if there is none.
+func special[T text](num T, p *Parser) (Special, int) {
+	var spec Special
+	var read int
+	for _, cand := range p.Specials {
+		if len(cand.Text) > read && common(num, cand.Text, p.CaseSensitive) == len(cand.Text) {
+			spec, read = cand, len(cand.Text)
+		}
+	}
+	return spec, read
+}
@@ -0,0 +1,82 @@
+package refloat_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+type specialTest struct {
+	inp    string
+	bits64 uint64
+	bits32 uint32
+	err    error
+}
+
+var specialtests = []specialTest{
+	{"1.#INF", 0x7ff0000000000000, 0x7f800000, nil},
+	{"-1.#INF", 0xfff0000000000000, 0xff800000, nil},
+	{"1.#INF00", 0x7ff0000000000000, 0x7f800000, nil},
+	{"-1.#IND", 0xfff8000000000000, 0xffc00000, nil},
+	{"-1.#IND00", 0xfff8000000000000, 0xffc00000, nil},
+	{"1.#QNAN", 0x7ff8000000000000, 0x7fc00000, nil},
+	{"1.#qnan0", 0x7ff8000000000000, 0x7fc00000, nil},
+	{"∞", 0x7ff0000000000000, 0x7f800000, nil},
+	{"-∞", 0xfff0000000000000, 0xff800000, nil},
+	{"+∞", 0x7ff0000000000000, 0x7f800000, nil},
+	{"inf.0", 0x7ff0000000000000, 0x7f800000, nil},
+	{"-inf.0", 0xfff0000000000000, 0xff800000, nil},
+	{"+nan.0", 0x7ff8000000000000, 0x7fc00000, nil},
+	{"-nan.0", 0xfff8000000000000, 0xffc00000, nil},
+	// the usual ones still work.
+	{"inf", 0x7ff0000000000000, 0x7f800000, nil},
+	{"-Infinity", 0xfff0000000000000, 0xff800000, nil},
+	{"1.5", 0x3ff8000000000000, 0x3fc00000, nil},
+	{"1.#INF0", 0, 0, ErrTrailing},
+	{"1.#IN", 0, 0, ErrTrailing},
+	{"∞∞", 0, 0, ErrTrailing},
+	{"\xe2\x88", 0, 0, ErrMantissa},
+	{"-nan", 0, 0, ErrSignedNaN},
+}
+
+func TestSpecials(t *testing.T) {
+	par := Parser{Specials: AltSpecials()}
+	for _, test := range specialtests {
+		f64, err := par.ParseFloat64(test.inp)
+		if !errors.Is(err, test.err) || (err == nil) != (test.err == nil) || err == nil && math.Float64bits(f64) != test.bits64 {
+			t.Errorf("Parser{Specials: AltSpecials()}.ParseFloat64(%q) = %#x, %v want %#x, %v", test.inp, math.Float64bits(f64), err, test.bits64, test.err)
+		}
+		f32, err := par.ParseFloat32(test.inp)
+		if !errors.Is(err, test.err) || (err == nil) != (test.err == nil) || err == nil && math.Float32bits(f32) != test.bits32 {
+			t.Errorf("Parser{Specials: AltSpecials()}.ParseFloat32(%q) = %#x, %v want %#x, %v", test.inp, math.Float32bits(f32), err, test.bits32, test.err)
+		}
+	}
+}
+
+func TestSpecialsCustom(t *testing.T) {
+	par := Parser{
+		Specials:      append(AltSpecials(), Special{Text: "INFINITE"}, Special{Text: "N/A", NaN: true}),
+		CaseSensitive: true,
+		NoSpecials:    true,
+	}
+	for _, test := range []struct {
+		inp string
+		out float64
+		err error
+	}{
+		{"INFINITE", math.Inf(1), nil},
+		{"-INFINITE", math.Inf(-1), nil},
+		{"infinite", 0, ErrSyntax},
+		{"N/A", math.NaN(), nil},
+		{"1.#INF", math.Inf(1), nil},
+		{"1.#inf", 0, ErrSyntax},
+		{"Inf", 0, ErrSyntax},
+	} {
+		out, err := par.ParseFloat(test.inp, 64)
+		if !isFloat64(out, test.out) || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("%+v.ParseFloat(%q, 64) = %v, %v want %v, %v", par, test.inp, out, err, test.out, test.err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package refloat_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	. "github.com/sugawarayuuta/refloat"
+)
+
+type underflowTest struct {
+	par  Parser
+	inp  string
+	size int
+	out  float64
+	err  error
+}
+
+var underflowtests = []underflowTest{
+	{Parser{Underflow: true}, "1e-400", 64, 0, ErrUnderflow},
+	{Parser{Underflow: true}, "-1e-400", 64, math.Copysign(0, -1), ErrUnderflow},
+	{Parser{Underflow: true}, "4e-320", 64, 4e-320, ErrUnderflow},
+	{Parser{Underflow: true}, "0x1p-1074", 64, 5e-324, ErrUnderflow},
+	{Parser{Underflow: true}, "0x1p-1076", 64, 0, ErrUnderflow},
+	{Parser{Underflow: true}, "2.2250738585072014e-308", 64, 2.2250738585072014e-308, nil},
+	{Parser{Underflow: true}, "2.2250738585072011e-308", 64, 2.225073858507201e-308, ErrUnderflow},
+	// rounds up to the smallest normal number.
+	{Parser{Underflow: true}, "2.2250738585072013e-308", 64, 2.2250738585072014e-308, nil},
+	{Parser{Underflow: true}, "1e-40", 32, float64(float32(1e-40)), ErrUnderflow},
+	{Parser{Underflow: true}, "1e-40", 64, 1e-40, nil},
+	{Parser{Underflow: true}, "0x1p-127", 32, 0x1p-127, ErrUnderflow},
+	{Parser{Underflow: true}, "0", 64, 0, nil},
+	{Parser{Underflow: true}, "0e-400", 64, 0, nil},
+	{Parser{Underflow: true}, "-0x0p-2000", 32, math.Copysign(0, -1), nil},
+	{Parser{Underflow: true}, "1e-5", 64, 1e-5, nil},
+	{Parser{Underflow: true, Exact: true}, "1e-400", 64, 0, ErrUnderflow},
+	{Parser{Underflow: true, Rounding: big.ToPositiveInf}, "1e-400", 64, 5e-324, ErrUnderflow},
+	{Parser{FlushToZero: true}, "4e-320", 64, 0, nil},
+	{Parser{FlushToZero: true}, "-4e-320", 64, math.Copysign(0, -1), nil},
+	{Parser{FlushToZero: true}, "0x1p-1074", 64, 0, nil},
+	{Parser{FlushToZero: true}, "1e-40", 32, 0, nil},
+	{Parser{FlushToZero: true}, "2.2250738585072013e-308", 64, 2.2250738585072014e-308, nil},
+	{Parser{FlushToZero: true}, "1e-300", 64, 1e-300, nil},
+	{Parser{FlushToZero: true, Rounding: big.ToPositiveInf}, "1e-400", 64, 0, nil},
+	{Parser{FlushToZero: true, Exact: true}, "0x1p-1074", 64, 0, ErrInexact},
+	{Parser{FlushToZero: true, Underflow: true}, "-4e-320", 64, math.Copysign(0, -1), ErrUnderflow},
+}
+
+func TestUnderflow(t *testing.T) {
+	for _, test := range underflowtests {
+		out, err := test.par.ParseFloat(test.inp, test.size)
+		if !isFloat64(out, test.out) || !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Errorf("%+v.ParseFloat(%q, %d) = %v, %v want %v, %v", test.par, test.inp, test.size, out, err, test.out, test.err)
+		}
+	}
+}
+
+func TestUnderflowRandom(t *testing.T) {
+	for _, inp := range roundingInputs() {
+		rat, ok := new(big.Rat).SetString(inp)
+		if !ok {
+			t.Fatalf("bad test input %q", inp)
+		}
+		for _, mode := range roundingModes {
+			for _, size := range []int{32, 64} {
+				want, over := roundRat(rat, size, mode)
+				norm := 0x1p-1022
+				if size == 32 {
+					norm = 0x1p-126
+				}
+				under := rat.Sign() != 0 && math.Abs(want) < norm
+				if under {
+					want = math.Copysign(0, want)
+				}
+				par := Parser{Rounding: mode, Underflow: true, FlushToZero: true}
+				out, err := par.ParseFloat(inp, size)
+				if math.Float64bits(out) != math.Float64bits(want) || over != errors.Is(err, ErrRange) || under != errors.Is(err, ErrUnderflow) {
+					t.Errorf("%+v.ParseFloat(%q, %d) = %v, %v want %v, underflow: %v", par, inp, size, out, err, want, under)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,656 @@
+package refloat
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+var (
+	// powers of 5 which fit in 64 bits.
+	pow5uint64 = [...]uint64{
+		1, 5, 25, 125,
+		625, 3125, 15625, 78125,
+		390625, 1953125, 9765625, 48828125,
+		244140625, 1220703125, 6103515625, 30517578125,
+		152587890625, 762939453125, 3814697265625, 19073486328125,
+		95367431640625, 476837158203125, 2384185791015625, 11920928955078125,
+		59604644775390625, 298023223876953125, 1490116119384765625, 7450580596923828125,
+	}
+	// 10^(2^k) and 10^-(2^k) as hi:lo*2^exp, where the top bit of hi is
+	// set. the bits below lo are cut off, which is less than 1 ulp.
+	pow10wide = [2][13]struct {
+		hi, lo uint64
+		exp    int
+	}{{
+		{0xa000000000000000, 0x0000000000000000, -124},
+		{0xc800000000000000, 0x0000000000000000, -121},
+		{0x9c40000000000000, 0x0000000000000000, -114},
+		{0xbebc200000000000, 0x0000000000000000, -101},
+		{0x8e1bc9bf04000000, 0x0000000000000000, -74},
+		{0x9dc5ada82b70b59d, 0xf020000000000000, -21},
+		{0xc2781f49ffcfa6d5, 0x3cbf6b71c76b25fb, 85},
+		{0x93ba47c980e98cdf, 0xc66f336c36b10137, 298},
+		{0xaa7eebfb9df9de8d, 0xddbb901b98feeab7, 723},
+		{0xe319a0aea60e91c6, 0xcc655c54bc5058f8, 1573},
+		{0xc976758681750c17, 0x650d3d28f18b50ce, 3274},
+		{0x9e8b3b5dc53d5de4, 0xa74d28ce329ace52, 6676},
+		{0xc46052028a20979a, 0xc94c153f804a4a92, 13479},
+	}, {
+		{0xcccccccccccccccc, 0xcccccccccccccccc, -131},
+		{0xa3d70a3d70a3d70a, 0x3d70a3d70a3d70a3, -134},
+		{0xd1b71758e219652b, 0xd3c36113404ea4a8, -141},
+		{0xabcc77118461cefc, 0xfdc20d2b36ba7c3d, -154},
+		{0xe69594bec44de15b, 0x4c2ebe687989a9b3, -181},
+		{0xcfb11ead453994ba, 0x67de18eda5814af2, -234},
+		{0xa87fea27a539e9a5, 0x3f2398d747b36224, -340},
+		{0xddd0467c64bce4a0, 0xac7cb3f6d05ddbde, -553},
+		{0xc0314325637a1939, 0xfa911155fefb5308, -978},
+		{0x9049ee32db23d21c, 0x7132d332e3f204d4, -1828},
+		{0xa2a682a5da57c0bd, 0x87a601586bd3f698, -3529},
+		{0xceae534f34362de4, 0x492512d4f2ead2cb, -6931},
+		{0xa6dd04c8d2ce9fde, 0x2de38123a1c3cffc, -13734},
+	}}
+)
+
+// parseWide parses num to f, which is wider than float64, and returns
+// its bits as hi:lo.
+func parseWide[T text](num T, p *Parser, f format, fnc string) (uint64, uint64, error) {
+	// max exponent + subnormal range + "mant" length + log10(2)*128.
+	limit := (f.bias+f.prec)*3/10 + 20 + 40
+	// Infs, NaNs and hexadecimals are converted to float64 there,
+	// which is only used to tell Infs and NaNs apart.
+	dec, done, f64, read, err := scan64(num, p, limit)
+	f64, err = whole(num, f64, read, err, fnc)
+	if err != nil && err.(*NumError).Err == ErrSyntax {
+		return 0, 0, err
+	}
+
+	var sign, offset int
+	if num[offset] == '+' {
+		offset++
+	} else if num[offset] == '-' {
+		offset++
+		sign = 1
+	}
+	if done && !dec.hex && math.IsInf(f64, 0) {
+		hi, lo := f.inf128(sign)
+		return hi, lo, nil
+	}
+	if done && !dec.hex {
+		if !p.NaNPayloads {
+			hi, lo, _ := f.nan128(sign, false, 0)
+			return hi, lo, nil
+		}
+		if len(p.Specials) != 0 {
+			if _, read := special(num[offset:], p); read != 0 {
+				hi, lo, _ := f.nan128(sign, false, 0)
+				return hi, lo, nil
+			}
+		}
+		// already validated, see above.
+		signal, load, over, _, _ := nanRead(num, offset, p)
+		hi, lo, fit := f.nan128(sign, signal, load)
+		if over || !fit {
+			return hi, lo, errorRange(fnc, num)
+		}
+		return hi, lo, nil
+	}
+
+	var hi, lo uint64
+	exp, trunc := dec.exp10, dec.trunc
+	if dec.hex {
+		hi, lo, exp, trunc = wideHex(num[offset:read], f)
+	} else {
+		lo = dec.mant
+	}
+	switch {
+	case dec.hex || lo == 0:
+		// 128 bits are enough for any format up to 126 bits of mantissa.
+	case !trunc && exp >= 0 && exp < len(pow5uint64):
+		// mant*10^exp == mant*5^exp*2^exp, where the product is exact.
+		hi, lo = bits.Mul64(lo, pow5uint64[exp])
+	case !trunc && exp < 0 && -exp < len(pow5uint64):
+		// mant*10^exp == mant/5^-exp*2^exp. the division gives more than
+		// 128 bits of the quotient, and whether it's exact.
+		pow := pow5uint64[-exp]
+		zero := bits.LeadingZeros64(lo)
+		q2, rem := bits.Div64(0, lo<<zero, pow)
+		q1, rem := bits.Div64(rem, 0, pow)
+		q0, rem := bits.Div64(rem, 0, pow)
+		log := bits.Len64(q2)
+		trunc = rem != 0 || q0<<(64-log) != 0
+		hi = q2<<(64-log) | q1>>log
+		lo = q1<<(64-log) | q0>>log
+		exp -= zero + 128 - log
+	default:
+		if !trunc && !p.Exact {
+			hi, lo, ok, err := f.compose128(lo, exp, sign, p)
+			if ok {
+				return hi, lo, errorOf(fnc, num, err)
+			}
+		}
+		mant, exp2, trunc2, _ := bigScale(num[:read], p, f, 128)
+		lo = mant.Uint64()
+		hi = mant.Rsh(mant, 64).Uint64()
+		exp, trunc = exp2, trunc2
+	}
+	hi, lo, err = f.pack128(hi, lo, exp, trunc, sign, p)
+	return hi, lo, errorOf(fnc, num, err)
+}
+
+// compose128 is compose64 for the formats wider than 64 bits, and returns
+// the bits as hi:lo. mant must be exact, and p must not be Exact, since
+// the bounds can't tell whether the result is.
+func (f format) compose128(mant uint64, exp10 int, sign int, p *Parser) (uint64, uint64, bool, error) {
+	hi, lo, exp := pow10wide128(exp10)
+	zero := bits.LeadingZeros64(mant)
+	mant <<= zero
+	exp -= zero
+	// the top 128 bits of the 192 bits product, which has 1 bit of
+	// leading zeros at most.
+	hip, lop := bits.Mul64(mant, hi)
+	mid, low := bits.Mul64(mant, lo)
+	var carry uint64
+	lop, carry = bits.Add64(lop, mid, 0)
+	hip += carry
+	flip := bits.LeadingZeros64(hip)
+	hi = hip<<flip | lop>>(64-flip)
+	lo = lop<<flip | low>>(64-flip)
+	exp += 64 - flip
+
+	// each of the cuts is less than 2^-127 of the result, or 2 ulps, and
+	// there are 26 of them at most.
+	uhi, ulo := hi, lo
+	ulo, carry = bits.Add64(ulo, 64, 0)
+	uhi, carry = bits.Add64(uhi, 0, carry)
+	if carry != 0 {
+		return 0, 0, false, nil
+	}
+	// rounding is monotonic in every mode, so the result is the same if the
+	// bounds agree on it.
+	hi, lo, err := f.pack128(hi, lo, exp, false, sign, p)
+	uhi, ulo, uerr := f.pack128(uhi, ulo, exp, false, sign, p)
+	if hi != uhi || lo != ulo || err != uerr {
+		return 0, 0, false, nil
+	}
+	return hi, lo, true, err
+}
+
+// pow10wide128 returns 10^exp10 as hi:lo*2^exp, where the top bit of hi is
+// set. it's below the exact value by 25 cuts of less than 2^-127 of it at
+// most, and exp10 must be within ±8191.
+func pow10wide128(exp10 int) (uint64, uint64, int) {
+	tab := &pow10wide[0]
+	if exp10 < 0 {
+		tab = &pow10wide[1]
+		exp10 = -exp10
+	}
+	hi, lo, exp := uint64(1)<<63, uint64(0), -127
+	for idx := 0; exp10 != 0; idx++ {
+		if exp10&1 != 0 {
+			ent := tab[idx]
+			var shift int
+			hi, lo, shift = mul128(hi, lo, ent.hi, ent.lo)
+			exp += ent.exp + shift
+		}
+		exp10 >>= 1
+	}
+	return hi, lo, exp
+}
+
+// mul128 returns the top 128 bits of (ahi:alo)*(bhi:blo), where the top bits
+// of ahi and bhi are set, and by how many bits it's shifted. the bits below
+// are cut off, which is less than 1 ulp.
+func mul128(ahi, alo, bhi, blo uint64) (uint64, uint64, int) {
+	h3, h2 := bits.Mul64(ahi, bhi)
+	m2, m1 := bits.Mul64(ahi, blo)
+	n2, n1 := bits.Mul64(alo, bhi)
+	l1, _ := bits.Mul64(alo, blo)
+	var c1, c2, c3, c4, c5 uint64
+	m1, c1 = bits.Add64(m1, n1, 0)
+	m1, c2 = bits.Add64(m1, l1, 0)
+	h2, c3 = bits.Add64(h2, m2, 0)
+	h2, c4 = bits.Add64(h2, n2, 0)
+	h2, c5 = bits.Add64(h2, c1+c2, 0)
+	h3 += c3 + c4 + c5
+	// the product of the 2 has 255 or 256 bits.
+	flip := int(^h3 >> 63)
+	return h3<<flip | h2>>(64-flip), h2<<flip | m1>>(64-flip), 128 - flip
+}
+
+// wideHex reads num, which is a hexadecimal validated by scan64 without its
+// sign, as hi:lo*2^exp with the first 128 bits. trunc reports whether
+// non-zero digits were cut off.
+func wideHex[T text](num T, f format) (uint64, uint64, int, bool) {
+	var (
+		hi, lo uint64
+		exp    int
+	)
+	var point, trunc bool
+	for offset := len("0x"); offset < len(num); offset++ {
+		char := num[offset]
+		if char == '.' && !point {
+			point = true
+			continue
+		}
+		if char == '_' {
+			continue
+		}
+		var dig uint64
+		switch {
+		case char-'0' <= '9'-'0':
+			dig = uint64(char - '0')
+		case char|0x20-'a' <= 'f'-'a':
+			dig = uint64(char|0x20-'a') + 10
+		default:
+			// 'p' is required after hexadecimals.
+			limit := f.bias + f.prec + 128 + 128
+			return hi, lo, wideExp(num[offset+1:], exp, limit, hi|lo == 0), trunc
+		}
+		if point {
+			exp -= 4
+		}
+		if hi>>60 != 0 {
+			trunc = trunc || dig != 0
+			exp += 4
+			continue
+		}
+		hi = hi<<4 | lo>>60
+		lo = lo<<4 | dig
+	}
+	return hi, lo, exp, trunc
+}
+
+// wideExp adds the exponent in num, which is after 'e' or 'p', to exp.
+// the result is kept in -limit..limit, and exp is kept as is for zeros.
+func wideExp[T text](num T, exp int, limit int, zero bool) int {
+	// exponent does not matter for 0.
+	if zero {
+		return exp
+	}
+	var offset, shift int
+	var esign bool
+	if num[offset] == '+' {
+		offset++
+	} else if num[offset] == '-' {
+		offset++
+		esign = true
+	}
+	for ; offset < len(num); offset++ {
+		char := num[offset]
+		if char == '_' {
+			continue
+		}
+		char -= '0'
+		if char > '9'-'0' {
+			break
+		}
+		// definitely an overflow.
+		if esign && exp-shift < -limit || !esign && exp+shift > limit {
+			continue
+		}
+		shift = shift*10 + int(char)
+	}
+	if esign {
+		return exp - shift
+	}
+	return exp + shift
+}
+
+// pack128 is pack for the formats wider than 64 bits,
+// where the mantissa is hi:lo and the bits are returned the same way.
+func (f format) pack128(hi, lo uint64, exp int, trunc bool, sign int, p *Parser) (uint64, uint64, error) {
+	prec, bias, width := f.prec, f.bias, f.width
+	shi, slo := lsh128(0, uint64(sign), width-1)
+	if hi|lo == 0 {
+		return shi, slo, nil
+	}
+
+	var cut bool
+	log := len128(hi, lo) - prec - 1 - 1
+	exp += log + bias + prec + 1
+	if log > 0 {
+		hi, lo, cut = rsh128(hi, lo, log)
+		trunc = trunc || cut
+	} else {
+		hi, lo = lsh128(hi, lo, -log)
+	}
+	if exp <= 0 {
+		hi, lo, cut = rsh128(hi, lo, 1-exp)
+		trunc = trunc || cut
+		exp = 0
+	}
+	inexact := trunc || lo&1 != 0
+	inc := round(lo, trunc, sign, p.Rounding)
+	hi, lo, _ = rsh128(hi, lo, 1)
+	var carry uint64
+	lo, carry = bits.Add64(lo, inc, 0)
+	hi += carry
+	if bit128(hi, lo, prec) != 0 && exp == 0 {
+		exp++
+	}
+	carry = bit128(hi, lo, prec+1)
+	hi, lo, _ = rsh128(hi, lo, int(carry))
+	exp += int(carry)
+	if !f.explicit {
+		// implicit bit; hide the highest 1.
+		mhi, mlo := lsh128(0, 1, prec)
+		hi &^= mhi
+		lo &^= mlo
+	}
+
+	lead := f.lead()
+	inf := 1<<(width-lead-1) - 1
+	if exp >= inf {
+		if !p.toInf(sign) {
+			// the largest finite number is just below Inf.
+			hi, lo = lsh128(0, uint64(inf), lead)
+			var borrow uint64
+			lo, borrow = bits.Sub64(lo, 1, 0)
+			hi -= borrow
+			return hi | shi, lo | slo, p.check(true, p.overflow())
+		}
+		hi, lo = f.inf128(sign)
+		return hi, lo, p.check(true, p.overflow())
+	}
+	ehi, elo := lsh128(0, uint64(exp), lead)
+	hi |= ehi | shi
+	lo |= elo | slo
+	if exp == 0 && p.FlushToZero {
+		// subnormals become zeros of the same sign.
+		hi, lo, inexact = shi, slo, true
+	}
+	if exp == 0 && p.Underflow {
+		return hi, lo, ErrUnderflow
+	}
+	if inexact && p.Exact {
+		return hi, lo, ErrInexact
+	}
+	return hi, lo, nil
+}
+
+// lead returns where the exponent begins in f, which is above the integer
+// bit if it's explicit.
+func (f format) lead() int {
+	if f.explicit {
+		return f.prec + 1
+	}
+	return f.prec
+}
+
+// inf128 returns the bits of Inf with the sign in f.
+func (f format) inf128(sign int) (uint64, uint64) {
+	lead := f.lead()
+	hi, lo := lsh128(0, 1<<(f.width-lead-1)-1, lead)
+	shi, slo := lsh128(0, uint64(sign), f.width-1)
+	if f.explicit {
+		// the integer bit is set for Infs and NaNs too.
+		lo |= 1 << f.prec
+	}
+	return hi | shi, lo | slo
+}
+
+// nan128 returns the bits of the NaN in f, and whether load fit in the payload.
+func (f format) nan128(sign int, signal bool, load uint64) (uint64, uint64, bool) {
+	fit := true
+	if f.prec-1 < 64 && load>>(f.prec-1) != 0 {
+		load &= 1<<(f.prec-1) - 1
+		fit = false
+	}
+	if signal && load == 0 {
+		// zero would be Inf.
+		load = 1
+	}
+	hi, lo := f.inf128(sign)
+	if !signal {
+		qhi, qlo := lsh128(0, 1, f.prec-1)
+		hi |= qhi
+		lo |= qlo
+	}
+	return hi, lo | load, fit
+}
+
+// frac128 returns the bits of hi:lo below the exponent of f.
+func (f format) frac128(hi, lo uint64) (uint64, uint64) {
+	mhi, mlo := lsh128(0, 1, f.prec)
+	mlo, borrow := bits.Sub64(mlo, 1, 0)
+	return hi & (mhi - borrow), lo & mlo
+}
+
+// digits returns the exact decimal of the finite bits hi:lo of f, ignoring
+// the sign, as its digits and the exponent of the first digit.
+func (f format) digits(hi, lo uint64) (string, int) {
+	lead := f.lead()
+	_, exp, _ := rsh128(hi, lo, lead)
+	exp &= 1<<(f.width-lead-1) - 1
+	fhi, flo := f.frac128(hi, lo)
+	var mant, temp big.Int
+	mant.SetUint64(fhi)
+	mant.Lsh(&mant, 64)
+	mant.Or(&mant, temp.SetUint64(flo))
+	if f.explicit {
+		// pseudo-denormals have the integer bit with the exponent of
+		// subnormals, and Intel reads them with the smallest exponent.
+		mant.SetBit(&mant, f.prec, uint(bit128(hi, lo, f.prec)))
+	} else if exp != 0 {
+		mant.SetBit(&mant, f.prec, 1)
+	}
+	if exp == 0 {
+		exp = 1
+	}
+	exp2 := int(exp) - f.bias - f.prec
+	if exp2 >= 0 {
+		mant.Lsh(&mant, uint(exp2))
+		exp2 = 0
+	} else {
+		// m*2^-n == m*5^n*10^-n.
+		temp.SetInt64(int64(-exp2))
+		mant.Mul(&mant, temp.Exp(fiv, &temp, nil))
+	}
+	digs := mant.String()
+	return digs, len(digs) - 1 + exp2
+}
+
+// shorten rounds digs, the digits of a decimal with the exponent exp,
+// to nearest even with n digits, and returns it in the 'e' format.
+func shorten(digs string, exp int, n int) string {
+	buf := []byte(digs)
+	for len(buf) < n {
+		buf = append(buf, '0')
+	}
+	if len(buf) > n {
+		rest := strings.TrimRight(digs[n+1:], "0")
+		if buf[n] > '5' || buf[n] == '5' && (rest != "" || (buf[n-1]-'0')%2 != 0) {
+			idx := n - 1
+			for ; idx >= 0 && buf[idx] == '9'; idx-- {
+				buf[idx] = '0'
+			}
+			if idx < 0 {
+				// "999" becomes "1000", which is "100" with the next exponent.
+				buf[0] = '1'
+				exp++
+			} else {
+				buf[idx]++
+			}
+		}
+		buf = buf[:n]
+	}
+	str := string(buf[:1])
+	if n > 1 {
+		str += "." + string(buf[1:])
+	}
+	return str + "e" + strconv.Itoa(exp)
+}
+
+// formatWide is formatShortest for the formats wider than float64.
+func formatWide(hi, lo uint64, f format) string {
+	lead := f.lead()
+	inf := uint64(1)<<(f.width-lead-1) - 1
+	_, top, _ := rsh128(hi, lo, lead)
+	top &= inf
+	fhi, flo := f.frac128(hi, lo)
+	// the integer bit, which is only stored in explicit formats.
+	one := uint64(1)
+	if f.explicit {
+		one = bit128(hi, lo, f.prec)
+	}
+	shi, slo := lsh128(0, 1, f.width-1)
+	ahi, alo := hi&^shi, lo&^slo
+	var sign string
+	if hi&shi|lo&slo != 0 {
+		sign = "-"
+	}
+	switch {
+	case top == inf && (fhi|flo != 0 || one == 0):
+		// pseudo-Infs and pseudo-NaNs are NaNs for Intel.
+		return "NaN"
+	case top == inf && sign != "":
+		return "-Inf"
+	case top == inf:
+		return "+Inf"
+	case top != 0 && one == 0:
+		// unnormals, which Intel doesn't take as numbers.
+		return "NaN"
+	case ahi|alo == 0:
+		return sign + "0"
+	}
+	if f.explicit && top == 0 && one != 0 {
+		// pseudo-denormals have the integer bit with the exponent of
+		// subnormals. they are the normal numbers of the smallest exponent.
+		ehi, elo := lsh128(0, 1, lead)
+		hi, lo = hi|ehi, lo|elo
+		ahi, alo = ahi|ehi, alo|elo
+	}
+	exact, exp := f.digits(hi, lo)
+	for digs := 1; ; digs++ {
+		str := sign + shorten(exact, exp, digs)
+		bhi, blo, err := parseWide(str, &std, f, "")
+		if bhi == hi && blo == lo && err == nil {
+			return layout(str)
+		}
+		if bhi&^shi > ahi || bhi&^shi == ahi && blo&^slo > alo {
+			continue
+		}
+		// the nearest decimal with digs digits is too far, but the next one
+		// on the other side may not be, if the gap there is wider.
+		str = up(str)
+		bhi, blo, err = parseWide(str, &std, f, "")
+		if bhi == hi && blo == lo && err == nil {
+			return layout(str)
+		}
+	}
+}
+
+// layout rewrites num, which is in the 'e' format, in the format of
+// strconv.FormatFloat(f, 'g', -1, 64).
+func layout(num string) string {
+	var neg bool
+	if num[0] == '-' {
+		neg = true
+		num = num[1:]
+	}
+	idx := len(num) - 1
+	for num[idx] != 'e' {
+		idx--
+	}
+	exp, _ := strconv.Atoi(num[idx+1:])
+	var digs []byte
+	point := -1
+	for _, char := range []byte(num[:idx]) {
+		if char == '.' {
+			point = len(digs)
+		} else {
+			digs = append(digs, char)
+		}
+	}
+	if point < 0 {
+		point = len(digs)
+	}
+	// up may have carried into a new digit before the point.
+	exp += point - 1
+	for len(digs) > 1 && digs[len(digs)-1] == '0' {
+		digs = digs[:len(digs)-1]
+	}
+
+	var buf []byte
+	if neg {
+		buf = append(buf, '-')
+	}
+	if exp < -4 || exp >= 6 {
+		buf = append(buf, digs[0])
+		if len(digs) > 1 {
+			buf = append(buf, '.')
+			buf = append(buf, digs[1:]...)
+		}
+		buf = append(buf, 'e')
+		if exp < 0 {
+			buf = append(buf, '-')
+			exp = -exp
+		} else {
+			buf = append(buf, '+')
+		}
+		if exp < 10 {
+			buf = append(buf, '0')
+		}
+		return string(strconv.AppendInt(buf, int64(exp), 10))
+	}
+	if exp < 0 {
+		buf = append(buf, "0."...)
+		for ; exp < -1; exp++ {
+			buf = append(buf, '0')
+		}
+		return string(append(buf, digs...))
+	}
+	for idx := 0; idx <= exp; idx++ {
+		if idx < len(digs) {
+			buf = append(buf, digs[idx])
+		} else {
+			buf = append(buf, '0')
+		}
+	}
+	if exp+1 < len(digs) {
+		buf = append(buf, '.')
+		buf = append(buf, digs[exp+1:]...)
+	}
+	return string(buf)
+}
+
+// rsh128 shifts hi:lo right by n, and reports whether non-zero bits were cut off.
+func rsh128(hi, lo uint64, n int) (uint64, uint64, bool) {
+	switch {
+	case n <= 0:
+		return hi, lo, false
+	case n >= 128:
+		return 0, 0, hi|lo != 0
+	case n >= 64:
+		return 0, hi >> (n - 64), lo != 0 || hi<<(128-n) != 0
+	}
+	return hi >> n, lo>>n | hi<<(64-n), lo<<(64-n) != 0
+}
+
+// lsh128 shifts hi:lo left by n, which is less than 128.
+func lsh128(hi, lo uint64, n int) (uint64, uint64) {
+	if n >= 64 {
+		return lo << (n - 64), 0
+	}
+	return hi<<n | lo>>(64-n), lo << n
+}
+
+// len128 is bits.Len64 for hi:lo.
+func len128(hi, lo uint64) int {
+	if hi != 0 {
+		return 64 + bits.Len64(hi)
+	}
+	return bits.Len64(lo)
+}
+
+// bit128 returns the nth bit of hi:lo.
+func bit128(hi, lo uint64, n int) uint64 {
+	if n >= 64 {
+		return hi >> (n - 64) & 1
+	}
+	return lo >> n & 1
+}